@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/felipead/hungarian-lottery/pkg/bench"
+	"github.com/felipead/hungarian-lottery/pkg/parsing"
+)
+
+func benchmark(cmd command, args []string) error {
+	var options parsing.LoadOptions
+	var benchOptions bench.Options
+
+	flags := newFlagSet(cmd)
+	flags.IntVar(&benchOptions.Draws, "draws", 1000, "`number` of random draws to process")
+	flags.Int64Var(&benchOptions.Seed, "seed", 1, "seed of the random draws")
+	registryFlags(flags, &options)
+	policyFlags(flags, &options)
+
+	paths, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if err = expectArgs(cmd, paths, 1, -1); err != nil {
+		return err
+	}
+	if benchOptions.Draws <= 0 {
+		return fmt.Errorf("%w: -draws must be positive", errUsage)
+	}
+
+	registry, summary, err := load(context.Background(), paths, options, false)
+	if err != nil {
+		return err
+	}
+	defer closeRegistry(registry)
+	registry.BeReadyForProcessing()
+
+	benchOptions.Tickets = summary.Players
+	result, err := bench.Run(registry, benchOptions)
+	if err != nil {
+		return err
+	}
+
+	output := struct {
+		Players int `json:"players"`
+		bench.Result
+	}{
+		Players: summary.Players,
+		Result:  result,
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
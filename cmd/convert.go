@@ -0,0 +1,30 @@
+package main
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	"github.com/felipead/hungarian-lottery/pkg/parsing"
+)
+
+func convert(cmd command, args []string) error {
+	var options parsing.LoadOptions
+
+	flags := newFlagSet(cmd)
+	policyFlags(flags, &options)
+
+	paths, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if err = expectArgs(cmd, paths, 2, 2); err != nil {
+		return err
+	}
+
+	summary, err := parsing.ConvertTextToBinary(paths[0], paths[1], options)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("converted %v into %v: %v", paths[0], paths[1], summary)
+	return nil
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+	"github.com/felipead/hungarian-lottery/pkg/parsing"
+)
+
+// newFlagSet creates the flags of the given command, which print its usage on -h.
+func newFlagSet(cmd command) *flag.FlagSet {
+	flags := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	flags.Usage = func() {
+		_, _ = fmt.Fprintf(flags.Output(), "Usage: hungarian-lottery %v %v\n\n%v\n\nFlags:\n", cmd.name, cmd.synopsis,
+			cmd.summary)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parseFlags parses the flags, which may be interleaved with the positional arguments, eg: "my-file.txt --debug".
+// The positional arguments are returned in order. Invalid flags are a usage error.
+func parseFlags(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+
+	for {
+		if err := flags.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: %v", errUsage, err)
+		}
+
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// expectArgs fails with a usage error unless there are between minimum and maximum positional arguments. A negative
+// maximum means there is no upper limit.
+func expectArgs(cmd command, args []string, minimum int, maximum int) error {
+	if len(args) < minimum || (maximum >= 0 && len(args) > maximum) {
+		return fmt.Errorf("%w: %v expects %v", errUsage, cmd.name, strings.TrimPrefix(cmd.synopsis, "[flags] "))
+	}
+	return nil
+}
+
+// policyFlags defines the flags determining how invalid lines are handled while loading.
+func policyFlags(flags *flag.FlagSet, options *parsing.LoadOptions) {
+	flags.BoolFunc("strict", "refuse to load if any line is invalid, instead of skipping it", func(string) error {
+		options.Policy = parsing.Strict
+		return nil
+	})
+	flags.StringVar(&options.QuarantineFile, "quarantine", "",
+		"write every rejected line to this `file`, along with its line number and reason")
+}
+
+// registryFlags defines the flags determining which kind of registry the players are loaded into.
+func registryFlags(flags *flag.FlagSet, options *parsing.LoadOptions) {
+	flags.BoolVar(&options.Compressed, "compressed", false, "only keep distinct combinations, saving memory")
+	flags.StringVar(&options.DiskDirectory, "disk", "",
+		"keep buckets in files under this `directory`, for more tickets than fit in memory")
+}
+
+// stringList is a flag which can be repeated, collecting all of its values.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// gameFiles holds the ticket files of a game.
+type gameFiles struct {
+	game  lottery.Game
+	paths []string
+}
+
+// gameList is a flag which can be repeated, collecting the ticket files of each game, given by its name, eg:
+// "hatoslotto=week-41.txt,retail.txt".
+type gameList []gameFiles
+
+func (l *gameList) String() string {
+	entries := make([]string, len(*l))
+	for i, files := range *l {
+		entries[i] = files.game.Name + "=" + strings.Join(files.paths, ",")
+	}
+	return strings.Join(entries, " ")
+}
+
+func (l *gameList) Set(value string) error {
+	name, paths, found := strings.Cut(value, "=")
+	if !found || paths == "" {
+		return fmt.Errorf("expected <game>=<path>[,<path>...], got '%v'", value)
+	}
+
+	game, ok := lottery.LookupGame(name)
+	if !ok {
+		return fmt.Errorf("%w: '%v'", parsing.ErrUnsupportedGame, name)
+	}
+	for _, files := range *l {
+		if files.game == game {
+			return fmt.Errorf("game '%v' given more than once", name)
+		}
+	}
+
+	*l = append(*l, gameFiles{game: game, paths: strings.Split(paths, ",")})
+	return nil
+}
+
+// csvDimensionList is a flag which can be repeated, collecting the additional dimension columns of CSV files, each
+// given by the dimension name and its column, eg: "region=county".
+type csvDimensionList []parsing.CSVDimension
+
+func (l *csvDimensionList) String() string {
+	entries := make([]string, len(*l))
+	for i, dimension := range *l {
+		entries[i] = dimension.Name + "=" + dimension.Column
+	}
+	return strings.Join(entries, " ")
+}
+
+func (l *csvDimensionList) Set(value string) error {
+	name, column, found := strings.Cut(value, "=")
+	if !found || name == "" || column == "" {
+		return fmt.Errorf("expected <dimension>=<column>, got '%v'", value)
+	}
+
+	*l = append(*l, parsing.CSVDimension{Name: name, Column: column})
+	return nil
+}
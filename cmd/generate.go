@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/felipead/hungarian-lottery/pkg/generator"
+)
+
+func generate(cmd command, args []string) (err error) {
+	var options generator.Options
+
+	flags := newFlagSet(cmd)
+	count := flags.Int("count", 10_000_000, "`number` of tickets to generate")
+	format := flags.String("format", "text", "`format` of the output file, either text or binary")
+	flags.Int64Var(&options.Seed, "seed", 1, "seed of the random tickets; the same seed always generates the same file")
+	flags.Float64Var(&options.BirthdayRatio, "birthday", 0, "`ratio` of tickets only picking numbers from 1 to 31")
+	flags.Float64Var(&options.PatternRatio, "patterns", 0, "`ratio` of tickets picking popular patterns, eg: 1 2 3 4 5")
+	flags.Float64Var(&options.DuplicatesRatio, "duplicates", 0, "`ratio` of tickets duplicating a previous ticket")
+
+	paths, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if err = expectArgs(cmd, paths, 1, 1); err != nil {
+		return err
+	}
+	if *count < 0 {
+		return fmt.Errorf("%w: -count must not be negative", errUsage)
+	}
+	if *format != "text" && *format != "binary" {
+		return fmt.Errorf("%w: unknown format '%v'", errUsage, *format)
+	}
+	if options.BirthdayRatio < 0 || options.PatternRatio < 0 || options.DuplicatesRatio < 0 ||
+		options.BirthdayRatio+options.PatternRatio+options.DuplicatesRatio > 1 {
+		return fmt.Errorf("%w: ratios must be positive, adding up to at most 1", errUsage)
+	}
+
+	file, err := os.Create(paths[0])
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	if *format == "binary" {
+		err = generator.New(options).WriteBinary(file, *count)
+	} else {
+		err = generator.New(options).WriteText(file, *count)
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Infof("generated %v tickets into %v", *count, paths[0])
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+	"github.com/felipead/hungarian-lottery/pkg/parsing"
+)
+
+func joker(cmd command, args []string) error {
+	var options parsing.LoadOptions
+
+	flags := newFlagSet(cmd)
+	policyFlags(flags, &options)
+
+	paths, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if err = expectArgs(cmd, paths, 1, 1); err != nil {
+		return err
+	}
+
+	log.Infof("loading tickets with Joker numbers from %v", paths[0])
+	registry, jokers, summary, err := parsing.LoadJokerFile(paths[0], options)
+	if err != nil {
+		return fmt.Errorf("unable to load file: %w — %v", err, summary)
+	}
+	log.Infof("%v", summary)
+
+	registry.BeReadyForProcessing()
+	jokers.BeReadyForProcessing()
+	fmt.Println("READY")
+
+	//
+	// Same as the run command, except that each draw must be followed by the drawn Joker number, and is answered
+	// with both reports, separated by a vertical bar.
+	//
+	scanner := bufio.NewScanner(os.Stdin)
+	picks := make([]lottery.Number, lottery.NumPicks)
+	digits := make([]lottery.Number, lottery.Joker.Digits)
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		hasJoker, err := parsing.ParseLineWithJoker(lottery.Otoslotto, line, picks, digits)
+		if err == nil && !hasJoker {
+			err = &parsing.ParseError{Err: parsing.ErrInvalidJokerNumber}
+		}
+		if err != nil {
+			var parseError *parsing.ParseError
+			if errors.As(err, &parseError) {
+				parseError.Line = lineNumber
+			}
+			return fmt.Errorf("could not parse input: %w — '%v'", err, line)
+		}
+
+		fmt.Printf("%v | %v\n", registry.ProcessLotteryPicks(picks), jokers.ProcessLotteryPicks(digits))
+		registry.ResetLastProcessing()
+		jokers.ResetLastProcessing()
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("I/O error: %w", err)
+	}
+	return nil
+}
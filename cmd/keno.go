@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+	"github.com/felipead/hungarian-lottery/pkg/parsing"
+)
+
+func keno(cmd command, args []string) error {
+	var options parsing.LoadOptions
+
+	flags := newFlagSet(cmd)
+	policyFlags(flags, &options)
+
+	paths, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if err = expectArgs(cmd, paths, 1, 1); err != nil {
+		return err
+	}
+
+	log.Infof("loading Kenó tickets from %v", paths[0])
+	registry, summary, err := parsing.LoadKenoFile(paths[0], options)
+	if err != nil {
+		return fmt.Errorf("unable to load file: %w — %v", err, summary)
+	}
+	log.Infof("%v", summary)
+
+	registry.BeReadyForProcessing()
+	fmt.Println("READY")
+
+	//
+	// Same as the run command, except that each draw has 20 numbers, and is answered with the Kenó report.
+	//
+	scanner := bufio.NewScanner(os.Stdin)
+	draw := make([]lottery.Number, lottery.Keno.NumPicks)
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		if err := parsing.ParseGameLine(lottery.Keno, line, draw); err != nil {
+			var parseError *parsing.ParseError
+			if errors.As(err, &parseError) {
+				parseError.Line = lineNumber
+			}
+			return fmt.Errorf("could not parse input: %w — '%v'", err, line)
+		}
+
+		fmt.Println(registry.ProcessKenoDraw(draw).String())
+		registry.ResetLastProcessing()
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("I/O error: %w", err)
+	}
+	return nil
+}
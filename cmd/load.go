@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+	"github.com/felipead/hungarian-lottery/pkg/parsing"
+)
+
+// load expands the given paths into ticket files, and loads them, until the context is done or the program is
+// interrupted. A single file is loaded on its own, unless bySource requires labeling its players by source file, as
+// several files always are.
+func load(
+	ctx context.Context, paths []string, options parsing.LoadOptions, bySource bool,
+) (lottery.Registry, parsing.LoadSummary, error) {
+	if options.Compressed && options.DiskDirectory != "" {
+		return nil, parsing.LoadSummary{}, fmt.Errorf("%w: -compressed and -disk are mutually exclusive", errUsage)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fileNames, err := parsing.ExpandSources(paths)
+	if err != nil {
+		return nil, parsing.LoadSummary{}, fmt.Errorf("unable to find input files: %w", err)
+	}
+
+	log.Infof("loading input files %v", strings.Join(fileNames, ", "))
+
+	var registry lottery.Registry
+	var summary parsing.LoadSummary
+	if len(fileNames) == 1 && !bySource {
+		registry, summary, err = parsing.LoadContext(ctx, fileNames[0], options)
+	} else {
+		registry, summary, err = parsing.LoadFilesContext(ctx, fileNames, options)
+	}
+	if err != nil {
+		return nil, summary, fmt.Errorf("unable to load file: %w — %v", err, summary)
+	}
+
+	log.Infof("%v", summary)
+	return registry, summary, nil
+}
+
+// closeRegistry closes a registry once no longer needed, if it holds resources such as the files of a disk registry.
+func closeRegistry(registry lottery.Registry) {
+	if closer, ok := registry.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Warnf("unable to close the registry: %v", err)
+		}
+	}
+}
@@ -1,72 +1,116 @@
 package main
 
 import (
-	"bufio"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
-	"time"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
-
-	"github.com/felipead/hungarian-lottery/pkg/lottery"
-	"github.com/felipead/hungarian-lottery/pkg/parsing"
 )
 
-func main() {
-	fileName, debugMode := parseArgs()
-
-	log.Infof("loading input file %v", fileName)
-	registry, err := parsing.LoadFile(fileName)
-	if err != nil {
-		log.Fatalf("unable to load file: %v", err)
-	}
+const (
+	exitSuccess = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
 
-	registry.BeReadyForProcessing()
-	fmt.Println("READY")
+// errUsage is returned by commands given invalid arguments, which exit with [exitUsage].
+var errUsage = errors.New("invalid usage")
 
-	inputLoop(registry, debugMode)
+type command struct {
+	name     string
+	synopsis string
+	summary  string
+	run      func(cmd command, args []string) error
 }
 
-func parseArgs() (fileName string, debugMode bool) {
-	if len(os.Args) < 2 {
-		log.Fatalf("no input file specified")
-	}
-
-	fileName = os.Args[1]
-	if len(os.Args) > 2 {
-		debugMode = os.Args[2] == "--debug"
-	}
+var commands = []command{
+	{"run", "[flags] <file>...", "Load ticket files, then report the winners of each draw read from the standard input.",
+		run},
+	{"serve", "[flags] <file>... | -game=<name>=<path>... [flags]",
+		"Load ticket files, then report the winners of draws received over TCP.", serve},
+	{"convert", "[flags] <text-file> <binary-file>", "Convert a text ticket file into the binary ticket format.",
+		convert},
+	{"generate", "[flags] <output-file>", "Generate a file of random tickets, for testing and benchmarks.",
+		generate},
+	{"stats", "[flags] <file>...", "Load ticket files, then print statistics about the picked combinations.",
+		stats},
+	{"bench", "[flags] <file>...", "Load ticket files, then measure the latency of random draws, printed as JSON.",
+		benchmark},
+	{"verify", "[flags] <file>...", "Validate ticket files, failing if any line is invalid.",
+		verify},
+	{"replay", "-draw=<numbers> -report=<counts> [flags] <file>...",
+		"Replay a published draw against ticket files, confirming or refuting its report per tier.", replay},
+	{"winners", "-draw=<numbers> [flags] <file>...",
+		"Export the winners of a draw, with their prize amounts, as CSV or JSON Lines.", winners},
+	{"keno", "[flags] <file>", "Load a Kenó ticket file, then report the tickets of each draw by size and hits.",
+		keno},
+	{"joker", "[flags] <file>", "Load tickets with Joker numbers, then report the winners of each draw of both games.",
+		joker},
+}
 
-	return fileName, debugMode
+func main() {
+	os.Exit(execute(os.Args[1:]))
 }
 
-func inputLoop(registry lottery.Registry, debugMode bool) {
-	scanner := bufio.NewScanner(os.Stdin)
-	picks := make([]lottery.Number, lottery.NumPicks)
+// execute runs the command given by the arguments, returning its exit code. For backwards compatibility, if the
+// first argument is not a command, it is handled by the "run" command.
+func execute(args []string) int {
+	if len(args) == 0 {
+		printUsage(os.Stderr)
+		return exitUsage
+	}
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		if err := parsing.ParseLine(line, picks); err != nil {
-			log.Fatalf("could not parse input: %v — '%v'", err, line)
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		if len(args) > 1 {
+			return execute([]string{args[1], "-h"})
 		}
+		printUsage(os.Stdout)
+		return exitSuccess
+	}
 
-		var start time.Time
-		if debugMode {
-			start = time.Now()
-		}
+	cmd, ok := lookup(args[0])
+	if ok {
+		args = args[1:]
+	} else if strings.HasPrefix(args[0], "-") {
+		log.Errorf("unknown command: %v", args[0])
+		printUsage(os.Stderr)
+		return exitUsage
+	} else {
+		cmd, _ = lookup("run")
+	}
 
-		report := registry.ProcessLotteryPicks(picks)
-		fmt.Println(report.String())
+	err := cmd.run(cmd, args)
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return exitSuccess
+	case errors.Is(err, errUsage):
+		log.Error(err)
+		return exitUsage
+	default:
+		log.Error(err)
+		return exitFailure
+	}
+}
 
-		if debugMode {
-			elapsed := time.Since(start)
-			log.Infof("took: %v ms", elapsed.Milliseconds())
+func lookup(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
 		}
-
-		registry.ResetLastProcessing()
 	}
+	return command{}, false
+}
 
-	if err := scanner.Err(); err != nil {
-		log.Fatalf("I/O error: %v", err)
+func printUsage(output io.Writer) {
+	_, _ = fmt.Fprintf(output, "Usage: hungarian-lottery <command> [flags] [arguments]\n\nCommands:\n")
+	for _, cmd := range commands {
+		_, _ = fmt.Fprintf(output, "  %-10v %v\n", cmd.name, cmd.summary)
 	}
+	_, _ = fmt.Fprintf(output, "\nRun 'hungarian-lottery help <command>' for the flags of a command.\n"+
+		"'hungarian-lottery <file>...' is the same as 'hungarian-lottery run <file>...'.\n")
 }
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/felipead/hungarian-lottery/pkg/metrics"
+	"github.com/felipead/hungarian-lottery/pkg/parsing"
+)
+
+// serveMetrics exposes new metrics over HTTP, on the "/metrics" path of the given address, in the background.
+func serveMetrics(address string) *metrics.Metrics {
+	m := metrics.New()
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m)
+
+	go func() {
+		log.Infof("serving metrics on %v", address)
+		if err := http.ListenAndServe(address, mux); !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("unable to serve metrics: %v", err)
+		}
+	}()
+
+	return m
+}
+
+// recordLoad records the outcome of loading the ticket files of the given game.
+func recordLoad(m *metrics.Metrics, game string, summary parsing.LoadSummary) {
+	m.TicketsLoaded.With(game).Set(float64(summary.Players))
+	for reason, count := range summary.Rejections {
+		m.RejectedLines.With(game, reason.Error()).Add(uint64(count))
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/felipead/hungarian-lottery/pkg/audit"
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+	"github.com/felipead/hungarian-lottery/pkg/parsing"
+)
+
+func replay(cmd command, args []string) error {
+	//
+	// Skipping invalid lines would silently leave tickets out of the replay, so loading is strict, unless told
+	// otherwise, in which case rejected lines are reported, and the published report cannot be confirmed.
+	//
+	options := parsing.LoadOptions{Policy: parsing.Strict}
+
+	flags := newFlagSet(cmd)
+	draw := flags.String("draw", "", "the published `numbers` of the draw, eg: \"12 83 73 26 32\"")
+	published := flags.String("report", "", "the published `counts` of winners, in the output format, eg: \"20 1 0 1\"")
+	registryFlags(flags, &options)
+	policyFlags(flags, &options)
+	flags.BoolFunc("lenient", "skip invalid lines instead of refusing to load, although the report can then not be "+
+		"confirmed", func(string) error {
+		options.Policy = parsing.Lenient
+		return nil
+	})
+
+	paths, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if err = expectArgs(cmd, paths, 1, -1); err != nil {
+		return err
+	}
+
+	picks := make([]lottery.Number, lottery.NumPicks)
+	if err = parsing.ParseLine(*draw, picks); err != nil {
+		return fmt.Errorf("%w: invalid -draw: %w", errUsage, err)
+	}
+	report, err := lottery.ParseReport(*published)
+	if err != nil {
+		return fmt.Errorf("%w: invalid -report: %w", errUsage, err)
+	}
+
+	registry, summary, err := load(context.Background(), paths, options, false)
+	if err != nil {
+		return err
+	}
+	defer closeRegistry(registry)
+	registry.BeReadyForProcessing()
+
+	verification, err := audit.Replay(context.Background(), registry, picks, report)
+	if err != nil {
+		return fmt.Errorf("unable to replay the draw: %w", err)
+	}
+	verification.Rejected = summary.Rejected()
+	fmt.Println(verification.String())
+
+	if verification.Refuted() > 0 {
+		return fmt.Errorf("published report refuted in %v of %v tiers", verification.Refuted(), len(verification.Tiers))
+	}
+	if !verification.Confirmed() {
+		return fmt.Errorf("published report unconfirmed: %v ticket lines were rejected", verification.Rejected)
+	}
+	return nil
+}
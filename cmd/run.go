@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/felipead/hungarian-lottery/pkg/batch"
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+	"github.com/felipead/hungarian-lottery/pkg/parsing"
+)
+
+func run(cmd command, args []string) error {
+	var options parsing.LoadOptions
+	var groupBy stringList
+	var csvDimensions csvDimensionList
+
+	flags := newFlagSet(cmd)
+	debugMode := flags.Bool("debug", false, "print additional information, such as processing times")
+	bySource := flags.Bool("by-source", false, "break each report down by source file, same as -group-by=source")
+	flags.Var(&groupBy, "group-by", "break each report down by this `dimension`; may be repeated")
+	flags.Var(&csvDimensions, "csv-dimension", "label CSV tickets by an additional column, given as `name=column`, "+
+		"eg: region=county; may be repeated")
+	registryFlags(flags, &options)
+	flags.BoolVar(&options.IndexCombinations, "jackpot", false, "log how many tickets share the jackpot of each draw")
+	draws := flags.String("draws", "", "process the draws from this `file`, one per line, instead of the standard input")
+	output := flags.String("output", "", "with -draws, write one report per draw to this `file`, instead of stdout")
+	metricsAddress := flags.String("metrics", "", "serve Prometheus metrics over HTTP on this `address`, eg: :9090")
+	deadline := flags.Duration("deadline", 0, "abort a draw taking longer than this `duration`, eg: 100ms")
+	policyFlags(flags, &options)
+
+	paths, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if err = expectArgs(cmd, paths, 1, -1); err != nil {
+		return err
+	}
+	if *bySource {
+		groupBy = append(groupBy, parsing.SourceDimension)
+	}
+	if *draws != "" && len(groupBy) > 0 {
+		return fmt.Errorf("%w: reports of -draws cannot be broken down by -group-by or -by-source", errUsage)
+	}
+	options.Dimensions = csvDimensions
+
+	registry, summary, err := load(context.Background(), paths, options, *bySource)
+	if err != nil {
+		return err
+	}
+	defer closeRegistry(registry)
+
+	//
+	// Optional interfaces are asserted on the registry itself, since the instrumented registry hides them.
+	//
+	visitor, _ := registry.(lottery.MatchVisitor)
+	if *metricsAddress != "" {
+		m := serveMetrics(*metricsAddress)
+		recordLoad(m, lottery.Otoslotto.Name, summary)
+		registry = m.Instrument(lottery.Otoslotto.Name, registry)
+	}
+
+	registry.BeReadyForProcessing()
+
+	if *draws != "" {
+		return processDraws(registry, summary.Players, *draws, *output)
+	}
+	fmt.Println("READY")
+
+	dimensions := make([]*lottery.Dimension, 0, len(groupBy))
+	for _, name := range groupBy {
+		var dimension *lottery.Dimension
+		var ok bool
+		if summary.Metadata != nil {
+			dimension, ok = summary.Metadata.Lookup(name)
+		}
+		if !ok {
+			return fmt.Errorf("unable to group by '%v': no such dimension in the input files", name)
+		}
+		if visitor == nil {
+			return fmt.Errorf("unable to group by '%v': players are not kept individually by the registry", name)
+		}
+		dimensions = append(dimensions, dimension)
+	}
+
+	return inputLoop(registry, loopOptions{
+		debugMode:    *debugMode,
+		deadline:     *deadline,
+		visitor:      visitor,
+		dimensions:   dimensions,
+		combinations: summary.Combinations,
+		tickets:      summary.Players,
+	})
+}
+
+// processDraws processes all draws from a file, writing their reports to another file, or to the standard output.
+// Invalid draws, and reports not adding up to the tickets loaded, are logged, and fail the command only after all
+// other draws were processed.
+func processDraws(registry lottery.Registry, tickets int, drawsFileName string, outputFileName string) (err error) {
+	input, err := os.Open(drawsFileName)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = input.Close() }()
+
+	output := os.Stdout
+	if outputFileName != "" {
+		if output, err = os.Create(outputFileName); err != nil {
+			return err
+		}
+		defer func() {
+			if closeErr := output.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+	}
+
+	summary, err := batch.ProcessDraws(registry, tickets, input, output)
+	if err != nil {
+		return err
+	}
+
+	for _, drawErr := range summary.Errors {
+		log.Warnf("skipping draw: %v", drawErr)
+	}
+	log.Infof("processed %v of %v draws", summary.Processed(), summary.Draws)
+
+	if len(summary.Errors) > 0 {
+		return fmt.Errorf("%v of %v draws could not be processed", len(summary.Errors), summary.Draws)
+	}
+	return nil
+}
+
+type loopOptions struct {
+	debugMode bool
+
+	// deadline aborts draws taking longer than this, if positive.
+	deadline time.Duration
+
+	// visitor visits the matches of each player, to break down reports by each dimension, if any.
+	visitor    lottery.MatchVisitor
+	dimensions []*lottery.Dimension
+
+	// combinations, if indexed, tell how many tickets share the jackpot.
+	combinations *lottery.CombinationIndex
+
+	// tickets is the number of tickets loaded, which the matches of every report must add up to.
+	tickets int
+}
+
+// inputLoop processes the lottery picks from the standard input. If dimensions are given, the report is followed by
+// one line per label of each dimension. If combinations were indexed, the number of tickets sharing the jackpot is
+// logged as well. A draw exceeding the deadline is reported as an error line, and does not stop the loop, while any
+// other failure of the registry, eg: an I/O error of a disk registry, stops it after the error line. Every report
+// is checked for consistency, logging an error if its matches do not add up to the tickets loaded.
+func inputLoop(registry lottery.Registry, options loopOptions) error {
+	scanner := bufio.NewScanner(os.Stdin)
+	picks := make([]lottery.Number, lottery.NumPicks)
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		if err := parsing.ParseLine(line, picks); err != nil {
+			var parseError *parsing.ParseError
+			if errors.As(err, &parseError) {
+				parseError.Line = lineNumber
+			}
+			return fmt.Errorf("could not parse input: %w — '%v'", err, line)
+		}
+
+		var start time.Time
+		if options.debugMode {
+			start = time.Now()
+		}
+
+		report, err := process(registry, picks, options.deadline)
+		if err != nil {
+			fmt.Printf("ERROR %v\n", err)
+			if !errors.Is(err, lottery.ErrDeadlineExceeded) {
+				return fmt.Errorf("registry failed on draw '%v': %w", line, err)
+			}
+			log.Errorf("aborted draw '%v': %v", line, err)
+			continue
+		}
+		fmt.Println(report.String())
+
+		if options.debugMode {
+			elapsed := time.Since(start)
+			log.Infof("took: %v ms", elapsed.Milliseconds())
+			log.Infof("histogram of matches, from 0 up to %v: %v", lottery.NumPicks, report.Histogram())
+		}
+
+		if err := lottery.CheckConsistency(report, options.tickets); err != nil {
+			log.Errorf("draw '%v': %v", line, err)
+		}
+
+		if options.combinations != nil {
+			log.Infof("jackpot shared by %v tickets", options.combinations.Count(picks))
+		}
+
+		if len(options.dimensions) > 0 {
+			for _, breakdown := range lottery.BreakDownBy(options.visitor, options.dimensions, options.tickets) {
+				fmt.Println(breakdown.String())
+			}
+		}
+
+		registry.ResetLastProcessing()
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("I/O error: %w", err)
+	}
+	return nil
+}
+
+// process processes the lottery picks, aborting if it takes longer than the deadline, if positive, or if the registry
+// fails, eg: on I/O errors of a disk registry. An aborted draw leaves the registry reset.
+func process(registry lottery.Registry, picks []lottery.Number, deadline time.Duration) (lottery.Report, error) {
+	ctx := context.Background()
+	if deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, deadline)
+		defer cancel()
+	}
+	return lottery.ProcessLotteryPicksContext(ctx, registry, picks)
+}
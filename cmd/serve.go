@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+	"github.com/felipead/hungarian-lottery/pkg/metrics"
+	"github.com/felipead/hungarian-lottery/pkg/parsing"
+	"github.com/felipead/hungarian-lottery/pkg/server"
+)
+
+func serve(cmd command, args []string) error {
+	var options parsing.LoadOptions
+	var games gameList
+
+	flags := newFlagSet(cmd)
+	address := flags.String("listen", ":7070", "TCP `address` to listen on for draws, one per line")
+	adminAddress := flags.String("admin", "", "TCP `address` to listen on for admin commands, such as RELOAD")
+	flags.Var(&games, "game",
+		"host a `game` along with its ticket files, eg: hatoslotto=week-41.txt,retail.txt; may be repeated")
+	registryFlags(flags, &options)
+	metricsAddress := flags.String("metrics", "", "serve Prometheus metrics over HTTP on this `address`, eg: :9090")
+	deadline := flags.Duration("deadline", 0, "abort a draw taking longer than this `duration`, eg: 100ms")
+	policyFlags(flags, &options)
+
+	paths, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	//
+	// Without -game, the ticket files are given as arguments, and draws are not prefixed by the name of a game.
+	//
+	routed := len(games) > 0
+	if !routed {
+		if err = expectArgs(cmd, paths, 1, -1); err != nil {
+			return err
+		}
+		games = gameList{{game: lottery.Otoslotto, paths: paths}}
+	} else if len(paths) > 0 {
+		return fmt.Errorf("%w: ticket files must be given by -game when hosting several games", errUsage)
+	}
+
+	var m *metrics.Metrics
+	if *metricsAddress != "" {
+		m = serveMetrics(*metricsAddress)
+	}
+
+	//
+	// Loading is canceled on shutdown, including reloads in progress.
+	//
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	router := server.NewRouter()
+	defer func() {
+		if err := router.Close(); err != nil {
+			log.Warnf("unable to close the registry: %v", err)
+		}
+	}()
+
+	servers := make([]*server.Server, 0, len(games))
+	for _, files := range games {
+		gameOptions := options
+		gameOptions.Game = files.game
+
+		s, err := server.NewReloadable(ctx, newLoader(gameOptions, m), files.paths)
+		if err != nil {
+			return err
+		}
+		s.Game = files.game
+		s.Deadline = *deadline
+
+		router.Handle(files.game.Name, s)
+		servers = append(servers, s)
+	}
+
+	var host interface {
+		Serve(listener net.Listener) error
+		ServeAdmin(listener net.Listener) error
+	} = servers[0]
+	if routed {
+		host = router
+	}
+
+	listener, err := net.Listen("tcp", *address)
+	if err != nil {
+		return err
+	}
+
+	if *adminAddress != "" {
+		adminListener, err := net.Listen("tcp", *adminAddress)
+		if err != nil {
+			return err
+		}
+		log.Infof("listening for admin commands on %v", adminListener.Addr())
+		go func() { _ = host.ServeAdmin(adminListener) }()
+	}
+
+	//
+	// Reloads the ticket files of all games on SIGHUP, in the background. Stops accepting connections on shutdown.
+	//
+	reloads := make(chan os.Signal, 1)
+	signal.Notify(reloads, syscall.SIGHUP)
+	go func() {
+		for {
+			select {
+			case <-reloads:
+				for _, s := range servers {
+					go reload(s)
+				}
+			case <-ctx.Done():
+				log.Infof("shutting down")
+				_ = listener.Close()
+				return
+			}
+		}
+	}()
+
+	log.Infof("listening on %v", listener.Addr())
+	fmt.Println("READY")
+
+	return host.Serve(listener)
+}
+
+// newLoader creates a loader of the ticket files of the game given by the options, recording the outcome into the
+// metrics, if any.
+func newLoader(options parsing.LoadOptions, m *metrics.Metrics) server.Loader {
+	return func(ctx context.Context, paths []string) (lottery.Registry, int, error) {
+		registry, summary, err := load(ctx, paths, options, false)
+		if err != nil {
+			return nil, 0, err
+		}
+		if m != nil {
+			recordLoad(m, options.Game.Name, summary)
+			registry = m.Instrument(options.Game.Name, registry)
+		}
+		return registry, summary.Players, nil
+	}
+}
+
+func reload(s *server.Server) {
+	log.Infof("reloading %v ticket files", s.Game.Name)
+	if err := s.Reload(nil); err != nil {
+		log.Errorf("unable to reload %v: %v", s.Game.Name, err)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/felipead/hungarian-lottery/pkg/parsing"
+)
+
+func stats(cmd command, args []string) error {
+	options := parsing.LoadOptions{IndexCombinations: true}
+
+	flags := newFlagSet(cmd)
+	popular := flags.Int("popular", 10, "list this `number` of the most popular combinations")
+	suspicious := flags.Int("suspicious", 0,
+		"list combinations picked at least this `number` of times from the same source file; 0 disables")
+	policyFlags(flags, &options)
+
+	paths, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if err = expectArgs(cmd, paths, 1, -1); err != nil {
+		return err
+	}
+
+	_, summary, err := load(context.Background(), paths, options, *suspicious > 0)
+	if err != nil {
+		return err
+	}
+
+	index := summary.Combinations
+	fmt.Println(summary.String())
+	fmt.Printf("%v distinct combinations picked\n", index.Distinct())
+
+	for _, combination := range index.MostPopular(*popular) {
+		fmt.Printf("popular: %v — picked by %v tickets\n", combination.Combination, combination.Count)
+	}
+
+	if *suspicious > 0 {
+		for _, duplicate := range index.SuspiciousDuplicates(*suspicious) {
+			fmt.Printf("suspicious: %v — picked by %v tickets from %v\n",
+				duplicate.Combination, duplicate.Count, duplicate.Source)
+		}
+	}
+
+	return nil
+}
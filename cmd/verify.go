@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/felipead/hungarian-lottery/pkg/parsing"
+)
+
+func verify(cmd command, args []string) error {
+	var options parsing.LoadOptions
+
+	flags := newFlagSet(cmd)
+	flags.StringVar(&options.QuarantineFile, "quarantine", "",
+		"write every rejected line to this `file`, along with its line number and reason")
+
+	paths, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if err = expectArgs(cmd, paths, 1, -1); err != nil {
+		return err
+	}
+
+	//
+	// Invalid lines are skipped rather than failing on the first one, so that all of them are reported at once.
+	//
+	_, summary, err := load(context.Background(), paths, options, false)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(summary.String())
+	if summary.Rejected() > 0 {
+		return fmt.Errorf("%w: %v of %v lines", parsing.ErrRejectedLines, summary.Rejected(), summary.Lines)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/felipead/hungarian-lottery/pkg/export"
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+	"github.com/felipead/hungarian-lottery/pkg/parsing"
+)
+
+func winners(cmd command, args []string) (err error) {
+	options := parsing.LoadOptions{KeepAccounts: true, KeepTicketIDs: true}
+
+	flags := newFlagSet(cmd)
+	draw := flags.String("draw", "", "the `numbers` of the draw, eg: \"12 83 73 26 32\"")
+	prizes := flags.String("prizes", "", "the prize `amount` of each tier, by matches, eg: \"5=1000000000,4=2000000\"")
+	pools := flags.String("pools", "", "the prize pool of each tier, by matches, shared among its winners; "+
+		"same `format` as -prizes")
+	formatName := flags.String("format", "csv", "the `format` of the winners file, either csv or jsonl")
+	output := flags.String("output", "", "write the winners to this `file`, instead of the standard output")
+	policyFlags(flags, &options)
+
+	paths, err := parseFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	if err = expectArgs(cmd, paths, 1, -1); err != nil {
+		return err
+	}
+
+	picks := make([]lottery.Number, lottery.NumPicks)
+	if err = parsing.ParseLine(*draw, picks); err != nil {
+		return fmt.Errorf("%w: invalid -draw: %w", errUsage, err)
+	}
+	format, err := export.ParseFormat(*formatName)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errUsage, err)
+	}
+	if *prizes != "" && *pools != "" {
+		return fmt.Errorf("%w: -prizes and -pools are mutually exclusive", errUsage)
+	}
+	amounts, err := export.ParsePrizes(*prizes + *pools)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errUsage, err)
+	}
+
+	registry, summary, err := load(context.Background(), paths, options, false)
+	if err != nil {
+		return err
+	}
+	defer closeRegistry(registry)
+
+	visitor, ok := registry.(lottery.WinnerVisitor)
+	if !ok {
+		return fmt.Errorf("unable to export winners: players are not kept individually by the %T registry", registry)
+	}
+	registry.BeReadyForProcessing()
+
+	report := registry.ProcessLotteryPicks(picks)
+	log.Infof("report: %v", report)
+	if *pools != "" {
+		amounts = amounts.Shared(report)
+	}
+
+	exportOptions := export.Options{Format: format, Prizes: amounts}
+	if summary.Metadata != nil {
+		exportOptions.Accounts, _ = summary.Metadata.LookupAttribute(parsing.AccountAttribute)
+		exportOptions.TicketIDs, _ = summary.Metadata.LookupAttribute(parsing.TicketAttribute)
+	}
+
+	file := os.Stdout
+	if *output != "" {
+		if file, err = os.Create(*output); err != nil {
+			return err
+		}
+		defer func() {
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+	}
+
+	count, err := export.Export(file, visitor, picks, exportOptions)
+	if err != nil {
+		return err
+	}
+	log.Infof("exported %v winners", count)
+	return nil
+}
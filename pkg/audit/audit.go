@@ -0,0 +1,119 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+)
+
+// Tier compares the number of winners of a prize tier, i.e., having a given number of matches, as published and as
+// replayed.
+type Tier struct {
+	Matches   int
+	Published int
+	Replayed  int
+}
+
+// Difference returns how many more winners were replayed than published, negative if fewer.
+func (t Tier) Difference() int {
+	return t.Replayed - t.Published
+}
+
+// Confirmed tells whether the published number of winners was replayed exactly.
+func (t Tier) Confirmed() bool {
+	return t.Difference() == 0
+}
+
+// Verification is the outcome of replaying a published draw.
+type Verification struct {
+	Draw []lottery.Number
+
+	// Tiers compares every prize tier, from the one having all matches down to the one having the fewest.
+	Tiers []Tier
+
+	// Rejected is the number of ticket lines rejected while loading, if any, in which case the replay is incomplete,
+	// and the published report cannot be confirmed.
+	Rejected int
+}
+
+// Confirmed tells whether the published report was replayed exactly, in all tiers, from all tickets.
+func (v Verification) Confirmed() bool {
+	return v.Refuted() == 0 && v.Rejected == 0
+}
+
+// Refuted returns the number of tiers whose published number of winners could not be replayed.
+func (v Verification) Refuted() int {
+	refuted := 0
+	for _, tier := range v.Tiers {
+		if !tier.Confirmed() {
+			refuted++
+		}
+	}
+	return refuted
+}
+
+// String formats the verification as a table with one line per tier, flagging every mismatch, followed by the
+// verdict.
+func (v Verification) String() string {
+	var output strings.Builder
+
+	numbers := make([]string, 0, len(v.Draw))
+	for _, pick := range v.Draw {
+		numbers = append(numbers, fmt.Sprintf("%v", pick))
+	}
+	output.WriteString(fmt.Sprintf("draw: %v\n", strings.Join(numbers, " ")))
+	output.WriteString(fmt.Sprintf("%-8v %12v %12v %12v\n", "matches", "published", "replayed", "difference"))
+
+	for _, tier := range v.Tiers {
+		output.WriteString(fmt.Sprintf("%-8v %12v %12v %+12d", tier.Matches, tier.Published, tier.Replayed,
+			tier.Difference()))
+		if !tier.Confirmed() {
+			output.WriteString("  MISMATCH")
+		}
+		output.WriteString("\n")
+	}
+
+	if v.Rejected > 0 {
+		output.WriteString(fmt.Sprintf("rejected: %v ticket lines, so the replay is incomplete\n", v.Rejected))
+	}
+
+	switch {
+	case v.Confirmed():
+		output.WriteString("CONFIRMED: all tiers match the published report")
+	case v.Refuted() > 0:
+		output.WriteString(fmt.Sprintf("REFUTED: %v of %v tiers differ from the published report", v.Refuted(),
+			len(v.Tiers)))
+	default:
+		output.WriteString(fmt.Sprintf("UNCONFIRMED: all tiers match the published report, but %v ticket lines were "+
+			"rejected", v.Rejected))
+	}
+
+	return output.String()
+}
+
+// Replay processes a published draw again, against a registry ready for processing, eg: loaded from the ticket file
+// or binary snapshot of that week, and compares the outcome with the published report, tier by tier. The registry is
+// reset afterwards. Fails if the registry could not process the draw, eg: on an I/O error reading the buckets of a
+// disk registry, rather than comparing an empty report, or if the context is done.
+func Replay(
+	ctx context.Context, registry lottery.Registry, draw []lottery.Number, published lottery.Report,
+) (Verification, error) {
+	replayed, err := lottery.ProcessLotteryPicksContext(ctx, registry, draw)
+	if err != nil {
+		return Verification{}, err
+	}
+	defer registry.ResetLastProcessing()
+
+	verification := Verification{Draw: draw}
+	for matches := lottery.NumPicks; matches >= 2; matches-- {
+		verification.Tiers = append(verification.Tiers, Tier{
+			Matches:   matches,
+			Published: published.GetWinnersHaving(matches),
+			Replayed:  replayed.GetWinnersHaving(matches),
+		})
+	}
+
+	return verification, nil
+}
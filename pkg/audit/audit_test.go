@@ -0,0 +1,99 @@
+package audit
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+)
+
+func TestReplayConfirmsPublishedReport(t *testing.T) {
+	registry := newRegistry()
+	published, err := lottery.ParseReport("1 1 1 1")
+	assert.NoError(t, err)
+
+	verification, err := Replay(context.Background(), registry, []lottery.Number{11, 22, 33, 44, 55}, published)
+	assert.NoError(t, err)
+
+	assert.True(t, verification.Confirmed())
+	assert.Equal(t, "draw: 11 22 33 44 55\n"+
+		"matches     published     replayed   difference\n"+
+		"5                   1            1           +0\n"+
+		"4                   1            1           +0\n"+
+		"3                   1            1           +0\n"+
+		"2                   1            1           +0\n"+
+		"CONFIRMED: all tiers match the published report", verification.String())
+}
+
+func TestReplayRefutesPublishedReport(t *testing.T) {
+	registry := newRegistry()
+	published, err := lottery.ParseReport("3 1 0 1")
+	assert.NoError(t, err)
+
+	verification, err := Replay(context.Background(), registry, []lottery.Number{11, 22, 33, 44, 55}, published)
+	assert.NoError(t, err)
+
+	assert.False(t, verification.Confirmed())
+	assert.Equal(t, 2, verification.Refuted())
+	assert.Equal(t, []Tier{
+		{Matches: 5, Published: 1, Replayed: 1},
+		{Matches: 4, Published: 0, Replayed: 1},
+		{Matches: 3, Published: 1, Replayed: 1},
+		{Matches: 2, Published: 3, Replayed: 1},
+	}, verification.Tiers)
+	assert.Contains(t, verification.String(), "4                   0            1           +1  MISMATCH\n")
+	assert.Contains(t, verification.String(), "2                   3            1           -2  MISMATCH\n")
+	assert.Contains(t, verification.String(), "REFUTED: 2 of 4 tiers differ from the published report")
+
+	//
+	// The registry is reset after replaying, so that it can replay another draw.
+	//
+	verification, err = Replay(context.Background(), registry, []lottery.Number{11, 22, 33, 44, 55}, lottery.NewReport())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, verification.Tiers[0].Replayed)
+}
+
+func TestReplayCannotConfirmPublishedReportIfLinesWereRejected(t *testing.T) {
+	registry := newRegistry()
+	published, err := lottery.ParseReport("1 1 1 1")
+	assert.NoError(t, err)
+
+	verification, err := Replay(context.Background(), registry, []lottery.Number{11, 22, 33, 44, 55}, published)
+	assert.NoError(t, err)
+	verification.Rejected = 2
+
+	assert.False(t, verification.Confirmed())
+	assert.Equal(t, 0, verification.Refuted())
+	assert.Contains(t, verification.String(), "rejected: 2 ticket lines, so the replay is incomplete\n")
+	assert.Contains(t, verification.String(),
+		"UNCONFIRMED: all tiers match the published report, but 2 ticket lines were rejected")
+}
+
+func TestReplayFailIfRegistryCannotBeRead(t *testing.T) {
+	registry, err := lottery.NewDiskRegistry(t.TempDir(), 0)
+	assert.NoError(t, err)
+	registry.RegisterPlayer(1, []lottery.Number{11, 22, 33, 44, 55})
+	registry.BeReadyForProcessing()
+
+	//
+	// Closing the registry removes its bucket files, so they can no longer be read.
+	//
+	assert.NoError(t, registry.(io.Closer).Close())
+
+	_, err = Replay(context.Background(), registry, []lottery.Number{11, 22, 33, 44, 55}, lottery.NewReport())
+	assert.Error(t, err)
+}
+
+func newRegistry() lottery.Registry {
+	registry := lottery.NewRegistry()
+	registry.RegisterPlayer(1, []lottery.Number{11, 22, 33, 44, 55})
+	registry.RegisterPlayer(2, []lottery.Number{11, 22, 33, 44, 66})
+	registry.RegisterPlayer(3, []lottery.Number{11, 22, 33, 77, 66})
+	registry.RegisterPlayer(4, []lottery.Number{11, 22, 88, 77, 66})
+	registry.RegisterPlayer(5, []lottery.Number{11, 89, 88, 77, 66})
+	registry.BeReadyForProcessing()
+	return registry
+}
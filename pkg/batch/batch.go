@@ -0,0 +1,74 @@
+package batch
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+	"github.com/felipead/hungarian-lottery/pkg/parsing"
+)
+
+// Summary summarizes the outcome of processing a batch of draws.
+type Summary struct {
+	// Draws is the total number of draws, i.e., lines, in the input, valid or not.
+	Draws int
+
+	// Errors holds a [*parsing.ParseError] for every invalid draw, along with its line number, or an error wrapping
+	// [lottery.ErrInconsistentReport] for every draw whose report does not add up to the tickets, in input order.
+	Errors []error
+}
+
+// Processed returns the number of draws which were processed, having a consistent report.
+func (s Summary) Processed() int {
+	return s.Draws - len(s.Errors)
+}
+
+// ProcessDraws processes many draws against a registry ready for processing, eg: historical results for
+// back-testing. The input holds one draw per line, in the same format accepted by [parsing.ParseLine], and one report
+// is written to the output for each one of them, in the same order, so that line numbers match. Every report is
+// checked for consistency against the number of tickets loaded into the registry. An invalid draw, or an inconsistent
+// report, does not stop processing: its line in the output is an error message starting with "ERROR" instead, and
+// the error is collected in the summary. The returned error is only about reading the input or writing the output.
+func ProcessDraws(registry lottery.Registry, tickets int, input io.Reader, output io.Writer) (Summary, error) {
+	var summary Summary
+
+	scanner := bufio.NewScanner(input)
+	writer := bufio.NewWriter(output)
+	picks := make([]lottery.Number, lottery.NumPicks)
+
+	for scanner.Scan() {
+		summary.Draws++
+
+		var reply string
+		if err := parsing.ParseLine(scanner.Text(), picks); err != nil {
+			var parseError *parsing.ParseError
+			if errors.As(err, &parseError) {
+				parseError.Line = summary.Draws
+			}
+			summary.Errors = append(summary.Errors, err)
+			reply = fmt.Sprintf("ERROR %v", err)
+		} else {
+			report := registry.ProcessLotteryPicks(picks)
+			registry.ResetLastProcessing()
+
+			if err := lottery.CheckConsistency(report, tickets); err != nil {
+				summary.Errors = append(summary.Errors, fmt.Errorf("line %v: %w", summary.Draws, err))
+				reply = fmt.Sprintf("ERROR line %v: %v", summary.Draws, err)
+			} else {
+				reply = report.String()
+			}
+		}
+
+		if _, err := fmt.Fprintln(writer, reply); err != nil {
+			return summary, err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return summary, err
+	}
+
+	return summary, writer.Flush()
+}
@@ -0,0 +1,61 @@
+package batch
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+	"github.com/felipead/hungarian-lottery/pkg/parsing"
+)
+
+func TestProcessDrawsOneReportPerLine(t *testing.T) {
+	registry := lottery.NewRegistry()
+	registry.RegisterPlayer(1, []lottery.Number{11, 22, 33, 44, 55})
+	registry.RegisterPlayer(2, []lottery.Number{11, 22, 33, 44, 66})
+	registry.RegisterPlayer(3, []lottery.Number{11, 22, 77, 88, 66})
+	registry.BeReadyForProcessing()
+
+	input := strings.NewReader("11 22 33 44 55\n" +
+		"11 22 33 44 91\n" +
+		"11 22 77 88 66\n" +
+		"\n" +
+		"1 2 3 4 5\n")
+	var output bytes.Buffer
+
+	summary, err := ProcessDraws(registry, 3, input, &output)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "1 0 1 1\n"+
+		"ERROR line 2: field 5 '91': picked number is out of range\n"+
+		"1 1 0 1\n"+
+		"ERROR line 4: invalid quantity of picked numbers\n"+
+		"0 0 0 0\n", output.String())
+
+	assert.Equal(t, 5, summary.Draws)
+	assert.Equal(t, 3, summary.Processed())
+	assert.Len(t, summary.Errors, 2)
+	assert.ErrorIs(t, summary.Errors[0], parsing.ErrNumberOutOfRange)
+	assert.ErrorIs(t, summary.Errors[1], parsing.ErrInvalidQuantityOfNumbers)
+}
+
+func TestProcessDrawsFailIfReportIsInconsistent(t *testing.T) {
+	registry := lottery.NewRegistry()
+	registry.RegisterPlayer(1, []lottery.Number{11, 22, 33, 44, 55})
+	registry.RegisterPlayer(2, []lottery.Number{11, 22, 33, 44, 66})
+	registry.BeReadyForProcessing()
+
+	input := strings.NewReader("11 22 33 44 55\n1 2 3 4 5\n")
+	var output bytes.Buffer
+
+	summary, err := ProcessDraws(registry, 3, input, &output)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "ERROR line 1: inconsistent report: matches add up to 2 tickets, but there are 3\n"+
+		"ERROR line 2: inconsistent report: matches add up to 2 tickets, but there are 3\n", output.String())
+	assert.Equal(t, 0, summary.Processed())
+	assert.Len(t, summary.Errors, 2)
+	assert.ErrorIs(t, summary.Errors[0], lottery.ErrInconsistentReport)
+}
@@ -0,0 +1,128 @@
+package bench
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/felipead/hungarian-lottery/pkg/generator"
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+)
+
+var ErrInvalidDraws = errors.New("number of draws must be positive")
+
+// Options configures a benchmark.
+type Options struct {
+	// Draws is how many random draws are processed, at least one.
+	Draws int
+
+	// Seed makes the random draws deterministic.
+	Seed int64
+
+	// Tickets is the number of tickets loaded into the registry, which the matches of every report must add up to.
+	Tickets int
+}
+
+// Result is the outcome of a benchmark, meant to be serialized as JSON.
+type Result struct {
+	Draws int `json:"draws"`
+
+	// Process and Reset are the latencies of [lottery.Registry.ProcessLotteryPicks] and
+	// [lottery.Registry.ResetLastProcessing], measured separately.
+	Process Latency `json:"process"`
+	Reset   Latency `json:"reset"`
+
+	// AllocationsPerDraw and BytesAllocatedPerDraw are the average heap allocations of processing and resetting a
+	// single draw.
+	AllocationsPerDraw    float64 `json:"allocations_per_draw"`
+	BytesAllocatedPerDraw float64 `json:"bytes_allocated_per_draw"`
+
+	// HeapBytes is the memory taken by live objects, mostly the registry, and SystemBytes is the total memory
+	// obtained from the operating system, after the benchmark.
+	HeapBytes   uint64 `json:"heap_bytes"`
+	SystemBytes uint64 `json:"system_bytes"`
+}
+
+// Latency summarizes the distribution of many measurements, in milliseconds.
+type Latency struct {
+	P50 float64 `json:"p50_ms"`
+	P95 float64 `json:"p95_ms"`
+	P99 float64 `json:"p99_ms"`
+	Max float64 `json:"max_ms"`
+}
+
+// Run processes random draws against the given registry, which must be ready for processing, measuring each one of
+// them. Once measured, every report is checked for consistency, failing with an error wrapping
+// [lottery.ErrInconsistentReport] if any of them does not add up to the tickets.
+func Run(registry lottery.Registry, options Options) (Result, error) {
+	if options.Draws <= 0 {
+		return Result{}, fmt.Errorf("%w: %v", ErrInvalidDraws, options.Draws)
+	}
+
+	draws := generator.New(generator.Options{Seed: options.Seed})
+	picks := make([]lottery.Number, lottery.NumPicks)
+
+	processing := make([]time.Duration, options.Draws)
+	resetting := make([]time.Duration, options.Draws)
+	reports := make([]lottery.Report, options.Draws)
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	for i := 0; i < options.Draws; i++ {
+		draws.Next(picks)
+
+		start := time.Now()
+		reports[i] = registry.ProcessLotteryPicks(picks)
+		processed := time.Now()
+		registry.ResetLastProcessing()
+
+		processing[i] = processed.Sub(start)
+		resetting[i] = time.Since(processed)
+	}
+
+	runtime.ReadMemStats(&after)
+
+	for i, report := range reports {
+		if err := lottery.CheckConsistency(report, options.Tickets); err != nil {
+			return Result{}, fmt.Errorf("draw %v: %w", i+1, err)
+		}
+	}
+
+	return Result{
+		Draws:                 options.Draws,
+		Process:               summarize(processing),
+		Reset:                 summarize(resetting),
+		AllocationsPerDraw:    float64(after.Mallocs-before.Mallocs) / float64(options.Draws),
+		BytesAllocatedPerDraw: float64(after.TotalAlloc-before.TotalAlloc) / float64(options.Draws),
+		HeapBytes:             after.HeapAlloc,
+		SystemBytes:           after.Sys,
+	}, nil
+}
+
+// summarize sorts the measurements to find their percentiles, by the nearest-rank method.
+func summarize(measurements []time.Duration) Latency {
+	if len(measurements) == 0 {
+		return Latency{}
+	}
+
+	sort.Slice(measurements, func(i, j int) bool { return measurements[i] < measurements[j] })
+
+	percentile := func(p int) float64 {
+		rank := (p*len(measurements) + 99) / 100
+		return milliseconds(measurements[max(rank, 1)-1])
+	}
+
+	return Latency{
+		P50: percentile(50),
+		P95: percentile(95),
+		P99: percentile(99),
+		Max: milliseconds(measurements[len(measurements)-1]),
+	}
+}
+
+func milliseconds(duration time.Duration) float64 {
+	return float64(duration) / float64(time.Millisecond)
+}
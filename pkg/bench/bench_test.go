@@ -0,0 +1,76 @@
+package bench
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/hungarian-lottery/pkg/generator"
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+)
+
+func TestSummarizePercentiles(t *testing.T) {
+	measurements := make([]time.Duration, 0, 200)
+	for i := 200; i >= 1; i-- {
+		measurements = append(measurements, time.Duration(i)*time.Millisecond)
+	}
+
+	assert.Equal(t, Latency{P50: 100, P95: 190, P99: 198, Max: 200}, summarize(measurements))
+}
+
+func TestSummarizeFewMeasurements(t *testing.T) {
+	assert.Equal(t, Latency{}, summarize(nil))
+	assert.Equal(t, Latency{P50: 3, P95: 3, P99: 3, Max: 3}, summarize([]time.Duration{3 * time.Millisecond}))
+	assert.Equal(t, Latency{P50: 1, P95: 2, P99: 2, Max: 2},
+		summarize([]time.Duration{2 * time.Millisecond, 1 * time.Millisecond}))
+}
+
+func TestRunRandomDraws(t *testing.T) {
+	registry := lottery.NewRegistry()
+	tickets := generator.New(generator.Options{Seed: 1})
+	picks := make([]lottery.Number, lottery.NumPicks)
+	for playerID := lottery.PlayerID(1); playerID <= 1000; playerID++ {
+		tickets.Next(picks)
+		registry.RegisterPlayer(playerID, picks)
+	}
+	registry.BeReadyForProcessing()
+
+	expected := registry.ProcessLotteryPicks([]lottery.Number{1, 2, 3, 4, 5}).String()
+	registry.ResetLastProcessing()
+
+	result, err := Run(registry, Options{Draws: 100, Seed: 1, Tickets: 1000})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 100, result.Draws)
+	assert.LessOrEqual(t, result.Process.P50, result.Process.P95)
+	assert.LessOrEqual(t, result.Process.P95, result.Process.P99)
+	assert.LessOrEqual(t, result.Process.P99, result.Process.Max)
+	assert.LessOrEqual(t, result.Reset.P99, result.Reset.Max)
+	assert.Greater(t, result.Process.Max, 0.0)
+	assert.Greater(t, result.HeapBytes, uint64(0))
+
+	//
+	// The registry must be left clean, as if no draw was processed.
+	//
+	assert.Equal(t, expected, registry.ProcessLotteryPicks([]lottery.Number{1, 2, 3, 4, 5}).String())
+}
+
+func TestRunFailIfReportIsInconsistent(t *testing.T) {
+	registry := lottery.NewRegistry()
+	registry.RegisterPlayer(1, []lottery.Number{1, 2, 3, 4, 5})
+	registry.BeReadyForProcessing()
+
+	_, err := Run(registry, Options{Draws: 10, Seed: 1, Tickets: 2})
+	assert.ErrorIs(t, err, lottery.ErrInconsistentReport)
+}
+
+func TestRunFailIfNoDraws(t *testing.T) {
+	registry := lottery.NewRegistry()
+	registry.BeReadyForProcessing()
+
+	for _, draws := range []int{0, -1} {
+		_, err := Run(registry, Options{Draws: draws})
+		assert.ErrorIs(t, err, ErrInvalidDraws)
+	}
+}
@@ -0,0 +1,246 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+)
+
+var ErrUnknownFormat = errors.New("unknown export format")
+
+var ErrInvalidPrizes = errors.New("invalid prizes")
+
+// Format is the file format of the winners file.
+type Format int
+
+const (
+	// CSV writes a header row, followed by one record per winner, with the matched numbers separated by spaces.
+	CSV Format = iota
+
+	// JSONLines writes one JSON object per line, per winner, with the matched numbers as an array.
+	JSONLines
+)
+
+// ParseFormat parses the name of a format, either "csv" or "jsonl".
+func ParseFormat(name string) (Format, error) {
+	switch name {
+	case "csv":
+		return CSV, nil
+	case "jsonl":
+		return JSONLines, nil
+	}
+	return 0, fmt.Errorf("%w: '%v'", ErrUnknownFormat, name)
+}
+
+// Prizes holds the prize amount of each tier, in the smallest unit of the currency, indexed by the number of matches.
+type Prizes [lottery.NumPicks + 1]int64
+
+// ParsePrizes parses the prize amount of each tier, given by its number of matches, separated by commas. For example,
+// "5=1000000000,4=2000000,3=20000,2=2000". Tiers not given have no prize.
+func ParsePrizes(value string) (Prizes, error) {
+	var prizes Prizes
+	if strings.TrimSpace(value) == "" {
+		return prizes, nil
+	}
+
+	for _, entry := range strings.Split(value, ",") {
+		matches, amount, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found {
+			return prizes, fmt.Errorf("%w: expected <matches>=<amount>, got '%v'", ErrInvalidPrizes, entry)
+		}
+
+		tier, err := strconv.Atoi(matches)
+		if err != nil || tier < 2 || tier > lottery.NumPicks {
+			return prizes, fmt.Errorf("%w: matches '%v' must be from 2 to %v", ErrInvalidPrizes, matches,
+				lottery.NumPicks)
+		}
+		prizes[tier], err = strconv.ParseInt(amount, 10, 64)
+		if err != nil || prizes[tier] < 0 {
+			return prizes, fmt.Errorf("%w: amount '%v' is not a non-negative number", ErrInvalidPrizes, amount)
+		}
+	}
+
+	return prizes, nil
+}
+
+// Shared treats the prizes as the pool of each tier, shared evenly among its winners, as reported, rounding down.
+// Returns the prize amount of each winner.
+func (p Prizes) Shared(report lottery.Report) Prizes {
+	var shared Prizes
+	for matches := 2; matches <= lottery.NumPicks; matches++ {
+		if winners := report.GetWinnersHaving(matches); winners > 0 {
+			shared[matches] = p[matches] / int64(winners)
+		}
+	}
+	return shared
+}
+
+// TierOf returns the prize tier of a winner having the given number of matches, where the first tier has all of
+// them.
+func TierOf(matches int) int {
+	return lottery.NumPicks - matches + 1
+}
+
+// Winner is a record of the winners file.
+type Winner struct {
+	TicketID  int64
+	AccountID string
+	Matched   []lottery.Number
+	Tier      int
+	Prize     int64
+}
+
+// Options configures the winners file.
+type Options struct {
+	Format Format
+	Prizes Prizes
+
+	// Accounts holds the account ID of each player, if known. See [parsing.AccountAttribute].
+	Accounts *lottery.Attribute
+
+	// TicketIDs holds the explicit ticket ID of each player, if known. See [parsing.TicketAttribute]. Players without
+	// one are identified by their player ID.
+	TicketIDs *lottery.Attribute
+}
+
+// Export writes the winners of the last processing of the given lottery picks, in ascending order of player ID, i.e.,
+// in the order the tickets were loaded.
+// Winners are streamed as they are visited, so that memory does not grow with their number. Returns how many were
+// written.
+func Export(output io.Writer, visitor lottery.WinnerVisitor, picks []lottery.Number, options Options) (int, error) {
+	buffered := bufio.NewWriter(output)
+
+	var writer winnerWriter
+	switch options.Format {
+	case CSV:
+		writer = newCSVWriter(buffered)
+	case JSONLines:
+		writer = newJSONLinesWriter(buffered)
+	default:
+		return 0, fmt.Errorf("%w: %v", ErrUnknownFormat, options.Format)
+	}
+
+	count := 0
+	var err error
+	visitor.VisitWinners(picks, func(playerID lottery.PlayerID, matched []lottery.Number) {
+		if err != nil {
+			return
+		}
+
+		winner := Winner{
+			TicketID: int64(playerID),
+			Matched:  matched,
+			Tier:     TierOf(len(matched)),
+			Prize:    options.Prizes[len(matched)],
+		}
+		if options.TicketIDs != nil {
+			if ticketID, parseErr := strconv.ParseInt(options.TicketIDs.ValueOf(playerID), 10, 64); parseErr == nil {
+				winner.TicketID = ticketID
+			}
+		}
+		if options.Accounts != nil {
+			winner.AccountID = options.Accounts.ValueOf(playerID)
+		}
+
+		if err = writer.write(winner); err == nil {
+			count++
+		}
+	})
+	if err != nil {
+		return count, err
+	}
+
+	if err = writer.flush(); err != nil {
+		return count, err
+	}
+	return count, buffered.Flush()
+}
+
+type winnerWriter interface {
+	write(winner Winner) error
+	flush() error
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+	record []string
+	header bool
+}
+
+func newCSVWriter(output io.Writer) *csvWriter {
+	return &csvWriter{writer: csv.NewWriter(output), record: make([]string, 5)}
+}
+
+func (w *csvWriter) write(winner Winner) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	numbers := make([]string, len(winner.Matched))
+	for i, number := range winner.Matched {
+		numbers[i] = strconv.Itoa(int(number))
+	}
+
+	w.record[0] = strconv.FormatInt(winner.TicketID, 10)
+	w.record[1] = winner.AccountID
+	w.record[2] = strings.Join(numbers, " ")
+	w.record[3] = strconv.Itoa(winner.Tier)
+	w.record[4] = strconv.FormatInt(winner.Prize, 10)
+	return w.writer.Write(w.record)
+}
+
+func (w *csvWriter) flush() error {
+	//
+	// The header is written even if there are no winners, so that the file is always valid.
+	//
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func (w *csvWriter) writeHeader() error {
+	if w.header {
+		return nil
+	}
+	w.header = true
+	return w.writer.Write([]string{"ticket_id", "account_id", "matched_numbers", "tier", "prize"})
+}
+
+type jsonLinesWriter struct {
+	encoder *json.Encoder
+}
+
+func newJSONLinesWriter(output io.Writer) *jsonLinesWriter {
+	return &jsonLinesWriter{encoder: json.NewEncoder(output)}
+}
+
+func (w *jsonLinesWriter) write(winner Winner) error {
+	//
+	// Numbers are bytes, which would be encoded as a base64 string, so they are converted to integers.
+	//
+	matched := make([]int, len(winner.Matched))
+	for i, number := range winner.Matched {
+		matched[i] = int(number)
+	}
+
+	return w.encoder.Encode(struct {
+		TicketID  int64  `json:"ticket_id"`
+		AccountID string `json:"account_id"`
+		Matched   []int  `json:"matched_numbers"`
+		Tier      int    `json:"tier"`
+		Prize     int64  `json:"prize"`
+	}{winner.TicketID, winner.AccountID, matched, winner.Tier, winner.Prize})
+}
+
+func (w *jsonLinesWriter) flush() error {
+	return nil
+}
@@ -0,0 +1,119 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+)
+
+func TestExportWinnersAsCSV(t *testing.T) {
+	registry, accounts := newRegistry()
+	picks := []lottery.Number{55, 11, 33, 22, 44}
+	registry.ProcessLotteryPicks(picks)
+
+	prizes, err := ParsePrizes("5=1000000000, 4=2000000,3=20000,2=2000")
+	assert.NoError(t, err)
+
+	var output bytes.Buffer
+	count, err := Export(&output, registry.(lottery.WinnerVisitor), picks, Options{
+		Format:   CSV,
+		Prizes:   prizes,
+		Accounts: accounts,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, "ticket_id,account_id,matched_numbers,tier,prize\n"+
+		"1,A-17,55 11 33 22 44,1,1000000000\n"+
+		"2,A-18,11 33 22 44,2,2000000\n"+
+		"5,,55 22,4,2000\n", output.String())
+}
+
+func TestExportWinnersAsJSONLines(t *testing.T) {
+	registry, _ := newRegistry()
+	picks := []lottery.Number{55, 11, 33, 22, 44}
+	report := registry.ProcessLotteryPicks(picks)
+
+	pools, err := ParsePrizes("5=1000,2=3001")
+	assert.NoError(t, err)
+
+	var output bytes.Buffer
+	count, err := Export(&output, registry.(lottery.WinnerVisitor), picks, Options{
+		Format: JSONLines,
+		Prizes: pools.Shared(report),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, ""+
+		`{"ticket_id":1,"account_id":"","matched_numbers":[55,11,33,22,44],"tier":1,"prize":1000}`+"\n"+
+		`{"ticket_id":2,"account_id":"","matched_numbers":[11,33,22,44],"tier":2,"prize":0}`+"\n"+
+		`{"ticket_id":5,"account_id":"","matched_numbers":[55,22],"tier":4,"prize":3001}`+"\n", output.String())
+}
+
+func TestExportWinnersWithTicketIDs(t *testing.T) {
+	registry, _ := newRegistry()
+	picks := []lottery.Number{55, 11, 33, 22, 44}
+	registry.ProcessLotteryPicks(picks)
+
+	tickets := lottery.NewAttribute("ticket")
+	tickets.Assign(1, "9000000000")
+	tickets.Assign(5, "17")
+
+	var output bytes.Buffer
+	count, err := Export(&output, registry.(lottery.WinnerVisitor), picks, Options{Format: CSV, TicketIDs: tickets})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, "ticket_id,account_id,matched_numbers,tier,prize\n"+
+		"9000000000,,55 11 33 22 44,1,0\n"+
+		"2,,11 33 22 44,2,0\n"+
+		"17,,55 22,4,0\n", output.String())
+}
+
+func TestExportWithoutWinners(t *testing.T) {
+	registry, _ := newRegistry()
+	picks := []lottery.Number{1, 2, 3, 4, 5}
+	registry.ProcessLotteryPicks(picks)
+
+	var output bytes.Buffer
+	count, err := Export(&output, registry.(lottery.WinnerVisitor), picks, Options{Format: CSV})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+	assert.Equal(t, "ticket_id,account_id,matched_numbers,tier,prize\n", output.String())
+}
+
+func TestParsePrizesFailIfInvalid(t *testing.T) {
+	for _, value := range []string{"5", "1=100", "6=100", "x=100", "5=-1", "5=lots"} {
+		_, err := ParsePrizes(value)
+		assert.ErrorIs(t, err, ErrInvalidPrizes, value)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("jsonl")
+	assert.NoError(t, err)
+	assert.Equal(t, JSONLines, format)
+
+	_, err = ParseFormat("xml")
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func newRegistry() (lottery.Registry, *lottery.Attribute) {
+	registry := lottery.NewRegistry()
+	registry.RegisterPlayer(1, []lottery.Number{11, 22, 33, 44, 55})
+	registry.RegisterPlayer(2, []lottery.Number{11, 22, 33, 44, 66})
+	registry.RegisterPlayer(4, []lottery.Number{11, 66, 77, 88, 89})
+	registry.RegisterPlayer(5, []lottery.Number{88, 55, 77, 22, 89})
+	registry.BeReadyForProcessing()
+
+	accounts := lottery.NewAttribute("account")
+	accounts.Assign(1, "A-17")
+	accounts.Assign(2, "A-18")
+
+	return registry, accounts
+}
@@ -0,0 +1,166 @@
+package generator
+
+import (
+	"bufio"
+	"io"
+	"math/rand"
+	"strconv"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+	"github.com/felipead/hungarian-lottery/pkg/parsing"
+)
+
+// Options configures how tickets are generated. The ratios are probabilities from 0 to 1, such that each ticket is
+// either a duplicate of a previous ticket, a popular pattern, a birthday ticket, or else picks uniformly random
+// numbers, in this order. The zero value generates uniformly random tickets.
+type Options struct {
+	// Seed makes the generated tickets deterministic: the same seed always generates the same tickets.
+	Seed int64
+
+	// BirthdayRatio is the ratio of tickets picking dates, such as birthdays, which only have numbers from 1 to 31.
+	BirthdayRatio float64
+
+	// PatternRatio is the ratio of tickets picking a popular pattern, which is an arithmetic progression, such as
+	// "1 2 3 4 5", "5 10 15 20 25" or a column of the ticket slip, such as "7 17 27 37 47".
+	PatternRatio float64
+
+	// DuplicatesRatio is the ratio of tickets picking exactly the same numbers as a previous ticket.
+	DuplicatesRatio float64
+}
+
+// maxBirthday is the highest number picked by birthday tickets.
+const maxBirthday = 31
+
+// patternSteps are the steps between the numbers of popular patterns.
+var patternSteps = []int{1, 2, 3, 5, 7, 10, 11}
+
+// poolSize is how many previous tickets are sampled for duplicates.
+const poolSize = 4096
+
+// Generator generates random lottery tickets, each one picking [lottery.NumPicks] distinct numbers.
+type Generator struct {
+	options Options
+	random  *rand.Rand
+
+	//
+	// Duplicates are copied from a pool of previous tickets, uniformly sampled among all of them by reservoir
+	// sampling, so that memory is bounded regardless of how many tickets are generated.
+	//
+	pool      [][lottery.NumPicks]lottery.Number
+	generated int
+}
+
+// New creates a new [Generator] with the given options.
+func New(options Options) *Generator {
+	return &Generator{
+		options: options,
+		random:  rand.New(rand.NewSource(options.Seed)),
+	}
+}
+
+// Next fills the given picks with the numbers of the next ticket.
+func (g *Generator) Next(picks []lottery.Number) {
+	chance := g.random.Float64()
+
+	switch {
+	case len(g.pool) > 0 && chance < g.options.DuplicatesRatio:
+		copy(picks, g.pool[g.random.Intn(len(g.pool))][:])
+	case chance < g.options.DuplicatesRatio+g.options.PatternRatio:
+		g.pattern(picks)
+	case chance < g.options.DuplicatesRatio+g.options.PatternRatio+g.options.BirthdayRatio:
+		g.uniform(picks, maxBirthday)
+	default:
+		g.uniform(picks, lottery.MaxNumber)
+	}
+
+	g.remember(picks)
+}
+
+// uniform picks distinct numbers from 1 to maxNumber, with the same probability.
+func (g *Generator) uniform(picks []lottery.Number, maxNumber int) {
+	//
+	// Rejection sampling is cheaper than shuffling all numbers, since collisions are rare when picking 5 out of 90.
+	//
+	var picked [lottery.MaxNumber + 1]bool
+	for i := 0; i < len(picks); {
+		pick := lottery.Number(g.random.Intn(maxNumber) + 1)
+		if !picked[pick] {
+			picked[pick] = true
+			picks[i] = pick
+			i++
+		}
+	}
+}
+
+// pattern picks an arithmetic progression, with one of the popular steps, starting from any number that fits.
+func (g *Generator) pattern(picks []lottery.Number) {
+	step := patternSteps[g.random.Intn(len(patternSteps))]
+	last := lottery.MaxNumber - step*(len(picks)-1)
+	start := g.random.Intn(last) + 1
+
+	for i := range picks {
+		picks[i] = lottery.Number(start + step*i)
+	}
+}
+
+func (g *Generator) remember(picks []lottery.Number) {
+	if g.options.DuplicatesRatio == 0 {
+		return
+	}
+
+	g.generated++
+	var ticket [lottery.NumPicks]lottery.Number
+	copy(ticket[:], picks)
+
+	if len(g.pool) < poolSize {
+		g.pool = append(g.pool, ticket)
+	} else if i := g.random.Intn(g.generated); i < poolSize {
+		g.pool[i] = ticket
+	}
+}
+
+// WriteText writes the given count of tickets to the output, in the text format accepted by the parser, one ticket
+// per line.
+func (g *Generator) WriteText(output io.Writer, count int) error {
+	writer := bufio.NewWriter(output)
+	picks := make([]lottery.Number, lottery.NumPicks)
+	line := make([]byte, 0, 3*lottery.NumPicks)
+
+	for i := 0; i < count; i++ {
+		g.Next(picks)
+
+		line = line[:0]
+		for j, pick := range picks {
+			if j > 0 {
+				line = append(line, ' ')
+			}
+			line = strconv.AppendInt(line, int64(pick), 10)
+		}
+		line = append(line, '\n')
+
+		if _, err := writer.Write(line); err != nil {
+			return err
+		}
+	}
+
+	return writer.Flush()
+}
+
+// WriteBinary writes the given count of tickets to the output, in the binary ticket format. The same seed generates
+// the same tickets as [Generator.WriteText].
+func (g *Generator) WriteBinary(output io.WriteSeeker, count int) error {
+	writer, err := parsing.NewBinaryWriter(output)
+	if err != nil {
+		return err
+	}
+
+	picks := make([]lottery.Number, lottery.NumPicks)
+	for i := 0; i < count; i++ {
+		g.Next(picks)
+		if err = writer.Write(picks); err != nil {
+			return err
+		}
+	}
+
+	return writer.Finish()
+}
@@ -0,0 +1,115 @@
+package generator
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+	"github.com/felipead/hungarian-lottery/pkg/parsing"
+)
+
+var realisticOptions = Options{Seed: 42, BirthdayRatio: 0.3, PatternRatio: 0.05, DuplicatesRatio: 0.1}
+
+func TestGenerateValidTickets(t *testing.T) {
+	generator := New(realisticOptions)
+	picks := make([]lottery.Number, lottery.NumPicks)
+
+	var output bytes.Buffer
+	assert.NoError(t, generator.WriteText(&output, 1000))
+
+	lines := strings.Split(strings.TrimSuffix(output.String(), "\n"), "\n")
+	assert.Len(t, lines, 1000)
+	for _, line := range lines {
+		assert.NoError(t, parsing.ParseLine(line, picks))
+	}
+}
+
+func TestGenerateDeterministicTickets(t *testing.T) {
+	var first, second, third bytes.Buffer
+	assert.NoError(t, New(Options{Seed: 7}).WriteText(&first, 100))
+	assert.NoError(t, New(Options{Seed: 7}).WriteText(&second, 100))
+	assert.NoError(t, New(Options{Seed: 8}).WriteText(&third, 100))
+
+	assert.Equal(t, first.String(), second.String())
+	assert.NotEqual(t, first.String(), third.String())
+}
+
+func TestGenerateBirthdayTickets(t *testing.T) {
+	generator := New(Options{Seed: 1, BirthdayRatio: 1})
+	picks := make([]lottery.Number, lottery.NumPicks)
+
+	for i := 0; i < 1000; i++ {
+		generator.Next(picks)
+		for _, pick := range picks {
+			assert.LessOrEqual(t, pick, lottery.Number(31))
+		}
+	}
+}
+
+func TestGeneratePatternTickets(t *testing.T) {
+	generator := New(Options{Seed: 1, PatternRatio: 1})
+	picks := make([]lottery.Number, lottery.NumPicks)
+
+	for i := 0; i < 1000; i++ {
+		generator.Next(picks)
+		step := int(picks[1]) - int(picks[0])
+		assert.Greater(t, step, 0)
+		assert.LessOrEqual(t, picks[len(picks)-1], lottery.Number(lottery.MaxNumber))
+		for j := 2; j < len(picks); j++ {
+			assert.Equal(t, step, int(picks[j])-int(picks[j-1]))
+		}
+	}
+}
+
+func TestGenerateDuplicateTickets(t *testing.T) {
+	generator := New(Options{Seed: 1, DuplicatesRatio: 0.5})
+	picks := make([]lottery.Number, lottery.NumPicks)
+	index := lottery.NewCombinationIndex()
+
+	for i := 0; i < 10000; i++ {
+		generator.Next(picks)
+		index.Add(picks, "")
+	}
+
+	assert.InDelta(t, 5000, index.Distinct(), 150)
+}
+
+func TestGenerateGoldenReports(t *testing.T) {
+	directory := t.TempDir()
+	textFileName := filepath.Join(directory, "tickets.txt")
+	binaryFileName := filepath.Join(directory, "tickets.bin")
+
+	textFile, err := os.Create(textFileName)
+	assert.NoError(t, err)
+	assert.NoError(t, New(realisticOptions).WriteText(textFile, 100_000))
+	assert.NoError(t, textFile.Close())
+
+	binaryFile, err := os.Create(binaryFileName)
+	assert.NoError(t, err)
+	assert.NoError(t, New(realisticOptions).WriteBinary(binaryFile, 100_000))
+	assert.NoError(t, binaryFile.Close())
+
+	for _, fileName := range []string{textFileName, binaryFileName} {
+		registry, summary, err := parsing.Load(fileName, parsing.LoadOptions{Policy: parsing.Strict})
+		assert.NoError(t, err)
+		assert.Equal(t, 100_000, summary.Players)
+
+		registry.BeReadyForProcessing()
+		for _, golden := range []struct {
+			picks  []lottery.Number
+			report string
+		}{
+			{[]lottery.Number{1, 2, 3, 4, 5}, "6488 696 41 14"},
+			{[]lottery.Number{7, 12, 19, 23, 30}, "6617 659 21 2"},
+			{[]lottery.Number{45, 58, 63, 77, 90}, "1414 47 4 0"},
+		} {
+			assert.Equal(t, golden.report, registry.ProcessLotteryPicks(golden.picks).String())
+			registry.ResetLastProcessing()
+		}
+	}
+}
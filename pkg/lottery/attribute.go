@@ -0,0 +1,45 @@
+package lottery
+
+// Attribute is a value of the players, such as their account ID. Unlike a [Dimension], its values may be distinct
+// for every player, so it is not meant for breaking down reports, but for looking up a given player, eg: a winner.
+type Attribute struct {
+	Name string
+
+	//
+	// All values are stored back to back in a single array, so that there is no allocation per player. The value of
+	// each player spans from its start to its end offset, where the player ID minus 1 is the index of the arrays, the
+	// same way as the dimensions do. Unassigned players have an empty span. At 8 bytes per player plus the values
+	// themselves, this is much less than a string per player.
+	//
+	data   []byte
+	starts []uint32
+	ends   []uint32
+}
+
+// NewAttribute creates a new [Attribute] without any players assigned to it.
+func NewAttribute(name string) *Attribute {
+	return &Attribute{Name: name}
+}
+
+// Assign assigns a value to a player. Values of all players may take up to 4 GiB.
+func (a *Attribute) Assign(playerID PlayerID, value string) {
+	if int(playerID) > len(a.starts) {
+		grow := int(playerID) - len(a.starts)
+		a.starts = append(a.starts, make([]uint32, grow)...)
+		a.ends = append(a.ends, make([]uint32, grow)...)
+	}
+
+	index := playerID - 1
+	a.starts[index] = uint32(len(a.data))
+	a.data = append(a.data, value...)
+	a.ends[index] = uint32(len(a.data))
+}
+
+// ValueOf returns the value assigned to the player, or an empty string if unassigned.
+func (a *Attribute) ValueOf(playerID PlayerID) string {
+	index := int(playerID) - 1
+	if index < 0 || index >= len(a.starts) {
+		return ""
+	}
+	return string(a.data[a.starts[index]:a.ends[index]])
+}
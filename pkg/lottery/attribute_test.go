@@ -0,0 +1,25 @@
+package lottery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttributeAssignValues(t *testing.T) {
+	attribute := NewAttribute("account")
+
+	attribute.Assign(3, "A-17")
+	attribute.Assign(5, "A-18")
+	attribute.Assign(1, "B-1")
+	attribute.Assign(3, "A-19")
+
+	assert.Equal(t, "account", attribute.Name)
+	assert.Equal(t, "B-1", attribute.ValueOf(1))
+	assert.Equal(t, "", attribute.ValueOf(2))
+	assert.Equal(t, "A-19", attribute.ValueOf(3))
+	assert.Equal(t, "", attribute.ValueOf(4))
+	assert.Equal(t, "A-18", attribute.ValueOf(5))
+	assert.Equal(t, "", attribute.ValueOf(6))
+	assert.Equal(t, "", attribute.ValueOf(0))
+}
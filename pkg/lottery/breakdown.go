@@ -0,0 +1,95 @@
+package lottery
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MatchVisitor is implemented by registries that keep track of the matches of each player, so that the last
+// processing of lottery picks can be inspected player by player.
+type MatchVisitor interface {
+
+	// VisitMatches invokes visit for every player having at least one match on the last processing of lottery picks.
+	// Must be invoked after [Registry.ProcessLotteryPicks], and before [Registry.ResetLastProcessing].
+	VisitMatches(visit func(playerID PlayerID, matches int))
+}
+
+// Breakdown is a [Report] for each label of a [Dimension].
+type Breakdown struct {
+	Dimension string
+
+	// Labels are all labels of the dimension, in the order they were first assigned. Players without a label are
+	// grouped under an empty label, which comes last.
+	Labels []string
+
+	Reports map[string]Report
+}
+
+// BreakDown breaks down the last processing of lottery picks by the labels of the given dimension, out of the given
+// number of players. Since this requires visiting every player with matches, it is slower than
+// [Registry.ProcessLotteryPicks], so it should be invoked after the main report was rendered.
+func BreakDown(visitor MatchVisitor, dimension *Dimension, players int) Breakdown {
+	return BreakDownBy(visitor, []*Dimension{dimension}, players)[0]
+}
+
+// BreakDownBy breaks down the last processing of lottery picks by each one of the given dimensions, out of the given
+// number of players, visiting every player with matches only once. Returns one [Breakdown] per dimension, in the
+// same order. Players without matches are not visited, so they are counted from the number of players having each
+// label instead, in order for the reports of each breakdown to add up to the number of players.
+func BreakDownBy(visitor MatchVisitor, dimensions []*Dimension, players int) []Breakdown {
+	breakdowns := make([]Breakdown, len(dimensions))
+
+	for i, dimension := range dimensions {
+		breakdowns[i] = Breakdown{
+			Dimension: dimension.Name,
+			Labels:    append([]string{}, dimension.Labels()...),
+			Reports:   make(map[string]Report, len(dimension.Labels())),
+		}
+		for _, label := range breakdowns[i].Labels {
+			breakdowns[i].Reports[label] = NewReport()
+		}
+	}
+
+	visitor.VisitMatches(func(playerID PlayerID, matches int) {
+		for i, dimension := range dimensions {
+			breakdowns[i].reportOf(dimension.LabelOf(playerID)).IncrementWinnersHaving(matches)
+		}
+	})
+
+	//
+	// Whatever was not visited in each group has no matches.
+	//
+	for i, dimension := range dimensions {
+		for label, count := range dimension.countPlayers(players) {
+			report := breakdowns[i].reportOf(label)
+			report.AddWinnersHaving(0, count-report.Total())
+		}
+	}
+
+	return breakdowns
+}
+
+// reportOf returns the report of the given label, adding the label if it has none yet.
+func (b *Breakdown) reportOf(label string) Report {
+	report, ok := b.Reports[label]
+	if !ok {
+		report = NewReport()
+		b.Reports[label] = report
+		b.Labels = append(b.Labels, label)
+	}
+	return report
+}
+
+// String formats the breakdown for textual representation, one line per label, in the same format as
+// [Report.String] prefixed by the dimension and label. For example, "channel=web: 20 1 0 0".
+func (b Breakdown) String() string {
+	lines := make([]string, 0, len(b.Labels))
+	for _, label := range b.Labels {
+		name := label
+		if name == "" {
+			name = "(unassigned)"
+		}
+		lines = append(lines, fmt.Sprintf("%v=%v: %v", b.Dimension, name, b.Reports[label].String()))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,88 @@
+package lottery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreakDownByDimension(t *testing.T) {
+	registry := NewRegistry()
+
+	registry.RegisterPlayer(1, []Number{44, 22, 17, 11, 55})
+	registry.RegisterPlayer(2, []Number{19, 11, 30, 16, 15})
+	registry.RegisterPlayer(3, []Number{55, 80, 33, 22, 11})
+	registry.RegisterPlayer(4, []Number{44, 33, 22, 11, 5})
+	registry.RegisterPlayer(5, []Number{10, 22, 55, 88, 6})
+	registry.RegisterPlayer(6, []Number{11, 22, 33, 44, 55})
+	registry.BeReadyForProcessing()
+
+	dimension := NewDimension("channel")
+	dimension.AssignRange(1, 3, "web")
+	dimension.AssignRange(4, 5, "retail")
+
+	report := registry.ProcessLotteryPicks([]Number{55, 11, 33, 22, 44})
+	breakdown := BreakDown(registry.(MatchVisitor), dimension, 6)
+	registry.ResetLastProcessing()
+
+	assert.Equal(t, "1 0 3 1", report.String())
+	assert.Equal(t, "channel", breakdown.Dimension)
+	assert.Equal(t, []string{"web", "retail", ""}, breakdown.Labels)
+	assert.Equal(t, "0 0 2 0", breakdown.Reports["web"].String())
+	assert.Equal(t, "1 0 1 0", breakdown.Reports["retail"].String())
+	assert.Equal(t, "0 0 0 1", breakdown.Reports[""].String())
+	assert.Equal(t, "channel=web: 0 0 2 0\nchannel=retail: 1 0 1 0\nchannel=(unassigned): 0 0 0 1", breakdown.String())
+}
+
+func TestBreakDownBySeveralDimensions(t *testing.T) {
+	registry := NewRegistry()
+
+	registry.RegisterPlayer(1, []Number{44, 22, 17, 11, 55})
+	registry.RegisterPlayer(2, []Number{55, 80, 33, 22, 11})
+	registry.RegisterPlayer(3, []Number{10, 22, 55, 88, 6})
+	registry.BeReadyForProcessing()
+
+	metadata := NewMetadata()
+	metadata.Dimension("region").AssignRange(1, 2, "Pest")
+	metadata.Dimension("region").Assign(3, "Baranya")
+	metadata.Dimension("channel").Assign(1, "web")
+	metadata.Dimension("channel").AssignRange(2, 3, "retail")
+
+	region, _ := metadata.Lookup("region")
+	channel, _ := metadata.Lookup("channel")
+
+	registry.ProcessLotteryPicks([]Number{55, 11, 33, 22, 44})
+	breakdowns := BreakDownBy(registry.(MatchVisitor), []*Dimension{region, channel}, 3)
+	registry.ResetLastProcessing()
+
+	assert.Len(t, breakdowns, 2)
+	assert.Equal(t, "region=Pest: 0 0 2 0\nregion=Baranya: 1 0 0 0", breakdowns[0].String())
+	assert.Equal(t, "channel=web: 0 0 1 0\nchannel=retail: 1 0 1 0", breakdowns[1].String())
+}
+
+func TestBreakDownCountsPlayersWithoutMatches(t *testing.T) {
+	registry := NewRegistry()
+
+	registry.RegisterPlayer(1, []Number{44, 22, 17, 11, 55})
+	registry.RegisterPlayer(2, []Number{19, 11, 30, 16, 15})
+	registry.RegisterPlayer(3, []Number{55, 80, 33, 22, 11})
+	registry.RegisterPlayer(4, []Number{44, 33, 22, 11, 5})
+	registry.RegisterPlayer(5, []Number{10, 22, 55, 88, 6})
+	registry.BeReadyForProcessing()
+
+	dimension := NewDimension("channel")
+	dimension.AssignRange(1, 2, "web")
+	dimension.Assign(3, "retail")
+	dimension.Assign(4, "mobile")
+
+	registry.ProcessLotteryPicks([]Number{1, 2, 3, 4, 5})
+	breakdown := BreakDown(registry.(MatchVisitor), dimension, 5)
+	registry.ResetLastProcessing()
+
+	assert.Equal(t, []string{"web", "retail", "mobile", ""}, breakdown.Labels)
+	assert.Equal(t, []int{2, 0, 0, 0, 0, 0}, breakdown.Reports["web"].Histogram())
+	assert.Equal(t, []int{1, 0, 0, 0, 0, 0}, breakdown.Reports["retail"].Histogram())
+	assert.Equal(t, []int{0, 1, 0, 0, 0, 0}, breakdown.Reports["mobile"].Histogram())
+	assert.Equal(t, []int{1, 0, 0, 0, 0, 0}, breakdown.Reports[""].Histogram())
+	assert.NoError(t, CheckConsistency(breakdown.Reports["web"], 2))
+}
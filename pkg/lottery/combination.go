@@ -0,0 +1,91 @@
+package lottery
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Combination identifies a set of [NumPicks] distinct lottery numbers, regardless of the order they were picked.
+// It is the rank of the set in the combinatorial number system, i.e., a unique number from 0 to [NumCombinations] - 1.
+// See [https://en.wikipedia.org/wiki/Combinatorial_number_system].
+type Combination uint32
+
+// NumCombinations is the number of distinct combinations of [NumPicks] out of [MaxNumber], currently 43,949,268.
+var NumCombinations = binomial(MaxNumber, NumPicks)
+
+// binomials holds the binomial coefficients C(n, k) for all n up to MaxNumber and k up to NumPicks.
+var binomials = func() (table [MaxNumber + 1][NumPicks + 1]int) {
+	for n := 0; n <= MaxNumber; n++ {
+		table[n][0] = 1
+		for k := 1; k <= NumPicks && k <= n; k++ {
+			table[n][k] = table[n-1][k-1] + table[n-1][k]
+		}
+	}
+	return table
+}()
+
+func binomial(n int, k int) int {
+	return binomials[n][k]
+}
+
+// CombinationOf returns the combination of the given picks, which must be [NumPicks] distinct numbers from 1 to
+// [MaxNumber], in any order.
+func CombinationOf(picks []Number) Combination {
+	sorted := sortPicks(picks)
+
+	//
+	// The rank of the sorted numbers c1 < c2 < ... < ck, counting from zero, is C(c1, 1) + C(c2, 2) + ... + C(ck, k).
+	//
+	rank := 0
+	for i, pick := range sorted {
+		rank += binomial(int(pick)-1, i+1)
+	}
+
+	return Combination(rank)
+}
+
+// Picks returns the numbers of this combination, in ascending order.
+func (c Combination) Picks() []Number {
+	picks := make([]Number, NumPicks)
+	rank := int(c)
+
+	//
+	// Greedily finds the largest number whose binomial coefficient fits in the remaining rank, from the last position
+	// to the first.
+	//
+	n := MaxNumber
+	for k := NumPicks; k >= 1; k-- {
+		for binomial(n-1, k) > rank {
+			n--
+		}
+		rank -= binomial(n-1, k)
+		picks[k-1] = Number(n)
+		n--
+	}
+
+	return picks
+}
+
+// String formats the combination as its numbers in ascending order, separated by spaces. For example, "1 2 3 4 5".
+func (c Combination) String() string {
+	fields := make([]string, 0, NumPicks)
+	for _, pick := range c.Picks() {
+		fields = append(fields, strconv.Itoa(int(pick)))
+	}
+	return strings.Join(fields, " ")
+}
+
+// sortPicks returns a sorted copy of the picks, without allocating memory, using insertion sort, which is the
+// fastest for so few numbers.
+func sortPicks(picks []Number) [NumPicks]Number {
+	var sorted [NumPicks]Number
+	copy(sorted[:], picks)
+
+	for i := 1; i < NumPicks; i++ {
+		for j := i; j > 0 && sorted[j-1] > sorted[j]; j-- {
+			sorted[j-1], sorted[j] = sorted[j], sorted[j-1]
+		}
+	}
+
+	return sorted
+}
@@ -0,0 +1,65 @@
+package lottery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumCombinations(t *testing.T) {
+	assert.Equal(t, 43949268, NumCombinations)
+}
+
+func TestCombinationOfLowestAndHighestPicks(t *testing.T) {
+	assert.Equal(t, Combination(0), CombinationOf([]Number{1, 2, 3, 4, 5}))
+	assert.Equal(t, Combination(1), CombinationOf([]Number{1, 2, 3, 4, 6}))
+	assert.Equal(t, Combination(NumCombinations-1), CombinationOf([]Number{86, 87, 88, 89, 90}))
+}
+
+func TestCombinationRegardlessOfOrder(t *testing.T) {
+	expected := CombinationOf([]Number{11, 22, 33, 44, 55})
+
+	assert.Equal(t, expected, CombinationOf([]Number{55, 44, 33, 22, 11}))
+	assert.Equal(t, expected, CombinationOf([]Number{33, 11, 55, 22, 44}))
+	assert.NotEqual(t, expected, CombinationOf([]Number{11, 22, 33, 44, 56}))
+}
+
+func TestCombinationPicks(t *testing.T) {
+	for _, picks := range [][]Number{
+		{1, 2, 3, 4, 5},
+		{86, 87, 88, 89, 90},
+		{7, 35, 65, 47, 11},
+		{90, 1, 45, 2, 89},
+	} {
+		combination := CombinationOf(picks)
+		sorted := sortPicks(picks)
+		assert.Equal(t, sorted[:], combination.Picks())
+	}
+
+	assert.Equal(t, "7 11 35 47 65", CombinationOf([]Number{7, 35, 65, 47, 11}).String())
+}
+
+func TestCombinationRanksAreDense(t *testing.T) {
+	//
+	// Every rank of combinations up to 12 must be distinct, contiguous, and round trip.
+	//
+	seen := make(map[Combination]bool)
+	for a := Number(1); a <= 12; a++ {
+		for b := a + 1; b <= 12; b++ {
+			for c := b + 1; c <= 12; c++ {
+				for d := c + 1; d <= 12; d++ {
+					for e := d + 1; e <= 12; e++ {
+						combination := CombinationOf([]Number{a, b, c, d, e})
+						assert.False(t, seen[combination])
+						assert.Equal(t, []Number{a, b, c, d, e}, combination.Picks())
+						seen[combination] = true
+					}
+				}
+			}
+		}
+	}
+
+	for rank := 0; rank < len(seen); rank++ {
+		assert.True(t, seen[Combination(rank)])
+	}
+}
@@ -0,0 +1,135 @@
+package lottery
+
+import (
+	"runtime"
+	"slices"
+)
+
+type compressedRegistry struct {
+	//
+	// Keeps the combination of every registered player, where the player ID minus 1 is the index of the array, the
+	// same way as the regular registry does for counting matches. The value is the combination plus 1, so that 0
+	// means unregistered. These are sorted and compacted into distinct combinations right before processing, and then
+	// released, so HasPlayerPick can only answer during registration. At 4 bytes per player, this is much less than
+	// the buckets of the regular registry.
+	//
+	players []Combination
+	ready   bool
+
+	//
+	// Many players pick the same combination, so we only keep each distinct combination once, along with its
+	// multiplicity, i.e., how many players picked it. Memory and processing time then scale with the number of
+	// distinct combinations, rather than the number of players.
+	//
+	combinations   []Combination
+	multiplicities []int
+
+	//
+	// The same bucket sort as the regular registry, except that each bucket holds the indexes of the distinct
+	// combinations having that number, and matches are counted per distinct combination.
+	//
+	buckets [MaxNumber][]int32
+	matches []uint8
+}
+
+// NewCompressedRegistry creates a new lottery registry which only keeps the distinct combinations picked by players,
+// along with how many players picked each one of them. Reports are identical to the regular registry, but memory
+// and processing time scale with the number of distinct combinations, which pays off when many players pick the
+// same combinations. The expected number of players, if known, avoids array resizing during registration.
+//
+// Since matches are not counted per player, picks must be distinct numbers, as validated by the parser, and it does
+// not implement [MatchVisitor]. Players are forgotten once ready for processing, after which HasPlayerPick is always
+// false.
+func NewCompressedRegistry(expectedPlayers int) Registry {
+	return &compressedRegistry{
+		players: make([]Combination, 0, expectedPlayers),
+	}
+}
+
+func (r *compressedRegistry) RegisterPlayer(playerID PlayerID, picks []Number) {
+	if int(playerID) > len(r.players) {
+		r.players = append(r.players, make([]Combination, int(playerID)-len(r.players))...)
+	}
+	r.players[playerID-1] = CombinationOf(picks) + 1
+}
+
+func (r *compressedRegistry) BeReadyForProcessing() {
+	if r.ready {
+		return
+	}
+	r.ready = true
+
+	//
+	// Sorting brings identical combinations together, so they can be counted in a single pass. The sorted copy is
+	// released once compacted.
+	//
+	sorted := make([]Combination, 0, len(r.players))
+	for _, combination := range r.players {
+		if combination != 0 {
+			sorted = append(sorted, combination-1)
+		}
+	}
+	r.players = nil
+	slices.Sort(sorted)
+
+	for i := 0; i < len(sorted); {
+		j := i + 1
+		for j < len(sorted) && sorted[j] == sorted[i] {
+			j++
+		}
+		r.combinations = append(r.combinations, sorted[i])
+		r.multiplicities = append(r.multiplicities, j-i)
+		i = j
+	}
+
+	//
+	// Knowing the allocation of each number beforehand, buckets are created without any resizing.
+	//
+	var allocation [MaxNumber]int
+	for _, combination := range r.combinations {
+		for _, pick := range combination.Picks() {
+			allocation[pick-1]++
+		}
+	}
+	for i := range r.buckets {
+		r.buckets[i] = make([]int32, 0, allocation[i])
+	}
+	for i, combination := range r.combinations {
+		for _, pick := range combination.Picks() {
+			r.buckets[pick-1] = append(r.buckets[pick-1], int32(i))
+		}
+	}
+
+	r.matches = make([]uint8, len(r.combinations))
+
+	runtime.GC()
+}
+
+func (r *compressedRegistry) ProcessLotteryPicks(picks []Number) Report {
+	for _, pick := range picks {
+		for _, index := range r.buckets[pick-1] {
+			r.matches[index]++
+		}
+	}
+
+	report := NewReport()
+	for i, count := range r.matches {
+		report.AddWinnersHaving(int(count), r.multiplicities[i])
+	}
+
+	return report
+}
+
+func (r *compressedRegistry) ResetLastProcessing() {
+	for i := range r.matches {
+		r.matches[i] = 0
+	}
+}
+
+func (r *compressedRegistry) HasPlayerPick(playerID PlayerID, pick Number) bool {
+	index := int(playerID) - 1
+	if index < 0 || index >= len(r.players) || r.players[index] == 0 {
+		return false
+	}
+	return slices.Contains((r.players[index] - 1).Picks(), pick)
+}
@@ -0,0 +1,110 @@
+package lottery
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompressedRegistryNoPlayerPicksGiven(t *testing.T) {
+	registry := NewCompressedRegistry(0)
+	registry.BeReadyForProcessing()
+
+	report := registry.ProcessLotteryPicks([]Number{11, 22, 33, 44, 55})
+
+	assert.Equal(t, "0 0 0 0", report.String())
+}
+
+func TestCompressedRegistryWeightsWinnersByMultiplicity(t *testing.T) {
+	registry := NewCompressedRegistry(8)
+
+	registry.RegisterPlayer(1, []Number{11, 22, 33, 44, 55})
+	registry.RegisterPlayer(2, []Number{55, 44, 33, 22, 11})
+	registry.RegisterPlayer(3, []Number{44, 33, 22, 11, 88})
+	registry.RegisterPlayer(4, []Number{33, 22, 11, 87, 88})
+	registry.RegisterPlayer(5, []Number{88, 11, 22, 33, 87})
+	registry.RegisterPlayer(6, []Number{22, 11, 86, 87, 88})
+	registry.RegisterPlayer(7, []Number{11, 85, 86, 87, 88})
+	registry.RegisterPlayer(8, []Number{33, 22, 11, 44, 55})
+	registry.BeReadyForProcessing()
+
+	report := registry.ProcessLotteryPicks([]Number{11, 22, 33, 44, 55})
+
+	assert.Equal(t, 3, report.GetWinnersHaving(5))
+	assert.Equal(t, 1, report.GetWinnersHaving(4))
+	assert.Equal(t, 2, report.GetWinnersHaving(3))
+	assert.Equal(t, 1, report.GetWinnersHaving(2))
+
+	registry.ResetLastProcessing()
+	report = registry.ProcessLotteryPicks([]Number{85, 86, 87, 88, 90})
+
+	assert.Equal(t, 0, report.GetWinnersHaving(5))
+	assert.Equal(t, 1, report.GetWinnersHaving(4))
+	assert.Equal(t, 1, report.GetWinnersHaving(3))
+	assert.Equal(t, 2, report.GetWinnersHaving(2))
+}
+
+func TestCompressedRegistryHasPlayerPick(t *testing.T) {
+	registry := NewCompressedRegistry(0)
+	registry.RegisterPlayer(1, []Number{11, 22, 33, 44, 55})
+	registry.RegisterPlayer(3, []Number{55, 44, 33, 22, 11})
+	registry.RegisterPlayer(4, []Number{1, 2, 3, 4, 5})
+
+	assert.True(t, registry.HasPlayerPick(1, 11))
+	assert.True(t, registry.HasPlayerPick(1, 55))
+	assert.False(t, registry.HasPlayerPick(1, 66))
+	assert.True(t, registry.HasPlayerPick(3, 22))
+	assert.True(t, registry.HasPlayerPick(4, 1))
+	assert.False(t, registry.HasPlayerPick(4, 11))
+	assert.False(t, registry.HasPlayerPick(2, 11))
+	assert.False(t, registry.HasPlayerPick(5, 1))
+	assert.False(t, registry.HasPlayerPick(0, 1))
+
+	registry.BeReadyForProcessing()
+	assert.False(t, registry.HasPlayerPick(1, 11))
+}
+
+func TestCompressedRegistryIsReadyForProcessingOnlyOnce(t *testing.T) {
+	registry := NewCompressedRegistry(0)
+	registry.RegisterPlayer(1, []Number{11, 22, 33, 44, 55})
+	registry.RegisterPlayer(2, []Number{55, 44, 33, 22, 11})
+
+	registry.BeReadyForProcessing()
+	registry.BeReadyForProcessing()
+
+	report := registry.ProcessLotteryPicks([]Number{11, 22, 33, 44, 55})
+	assert.Equal(t, []int{0, 0, 0, 0, 0, 2}, report.Histogram())
+}
+
+func TestCompressedRegistryMatchesRegularRegistry(t *testing.T) {
+	random := rand.New(rand.NewSource(42))
+	regular := NewRegistry()
+	compressed := NewCompressedRegistry(0)
+
+	//
+	// Picks are drawn from the lowest numbers only, so that many players pick the same combinations.
+	//
+	for playerID := PlayerID(1); playerID <= 5000; playerID++ {
+		picks := randomPicks(random, 12)
+		regular.RegisterPlayer(playerID, picks)
+		compressed.RegisterPlayer(playerID, picks)
+	}
+	regular.BeReadyForProcessing()
+	compressed.BeReadyForProcessing()
+
+	for draw := 0; draw < 20; draw++ {
+		picks := randomPicks(random, MaxNumber)
+		assert.Equal(t, regular.ProcessLotteryPicks(picks), compressed.ProcessLotteryPicks(picks))
+		regular.ResetLastProcessing()
+		compressed.ResetLastProcessing()
+	}
+}
+
+func randomPicks(random *rand.Rand, maxNumber int) []Number {
+	picks := make([]Number, 0, NumPicks)
+	for _, n := range random.Perm(maxNumber)[:NumPicks] {
+		picks = append(picks, Number(n+1))
+	}
+	return picks
+}
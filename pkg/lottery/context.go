@@ -0,0 +1,110 @@
+package lottery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrDeadlineExceeded is returned when processing or loading is aborted because it exceeded the deadline of its
+// context. It is distinct from a cancellation, which returns [context.Canceled].
+var ErrDeadlineExceeded = errors.New("deadline exceeded")
+
+// checkInterval is how many entries are processed between checks of the context, so that checking is cheap.
+const checkInterval = 1 << 16
+
+// ContextProcessor is implemented by registries whose processing of lottery picks can be aborted.
+type ContextProcessor interface {
+
+	// ProcessLotteryPicksContext processes the lottery picks as [Registry.ProcessLotteryPicks] does, but gives up
+	// as soon as the context is done. See [ProcessLotteryPicksContext].
+	ProcessLotteryPicksContext(ctx context.Context, picks []Number) (Report, error)
+}
+
+// ProcessLotteryPicksContext processes the lottery picks with the given registry, giving up as soon as the context is
+// done, with [ErrDeadlineExceeded] if its deadline was hit, or [context.Canceled]. An aborted processing leaves the
+// registry reset, ready for the next lottery picks. Registries which are not a [ContextProcessor] can only be aborted
+// before processing starts.
+func ProcessLotteryPicksContext(ctx context.Context, registry Registry, picks []Number) (Report, error) {
+	if processor, ok := registry.(ContextProcessor); ok {
+		return processor.ProcessLotteryPicksContext(ctx, picks)
+	}
+
+	if err := ContextError(ctx); err != nil {
+		return nil, err
+	}
+	return registry.ProcessLotteryPicks(picks), nil
+}
+
+// ContextError returns nil while the context is not done. Otherwise, it returns [ErrDeadlineExceeded] if its deadline
+// was hit, or the error of the context.
+func ContextError(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+	default:
+		return nil
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", ErrDeadlineExceeded, ctx.Err())
+	}
+	return ctx.Err()
+}
+
+func (r *registry) ProcessLotteryPicksContext(ctx context.Context, picks []Number) (Report, error) {
+	//
+	// Same as ProcessLotteryPicks, except that the context is checked periodically. Once aborted, the sparse array
+	// is reset, since partial counts would corrupt the next processing.
+	//
+	for _, pick := range picks {
+		for i, playerID := range r.buckets[pick-1] {
+			if i%checkInterval == 0 {
+				if err := ContextError(ctx); err != nil {
+					r.ResetLastProcessing()
+					return nil, err
+				}
+			}
+			r.playerMatches[playerID-1]++
+		}
+	}
+
+	report := newReport(r.game.NumPicks)
+	for i, count := range r.playerMatches {
+		if i%checkInterval == 0 {
+			if err := ContextError(ctx); err != nil {
+				r.ResetLastProcessing()
+				return nil, err
+			}
+		}
+		report.IncrementWinnersHaving(count)
+	}
+
+	return report, nil
+}
+
+func (r *compressedRegistry) ProcessLotteryPicksContext(ctx context.Context, picks []Number) (Report, error) {
+	for _, pick := range picks {
+		for i, index := range r.buckets[pick-1] {
+			if i%checkInterval == 0 {
+				if err := ContextError(ctx); err != nil {
+					r.ResetLastProcessing()
+					return nil, err
+				}
+			}
+			r.matches[index]++
+		}
+	}
+
+	report := NewReport()
+	for i, count := range r.matches {
+		if i%checkInterval == 0 {
+			if err := ContextError(ctx); err != nil {
+				r.ResetLastProcessing()
+				return nil, err
+			}
+		}
+		report.AddWinnersHaving(int(count), r.multiplicities[i])
+	}
+
+	return report, nil
+}
@@ -0,0 +1,65 @@
+package lottery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessLotteryPicksContext(t *testing.T) {
+	for _, registry := range []Registry{NewRegistry(), NewCompressedRegistry(0)} {
+		registry.RegisterPlayer(1, []Number{11, 22, 33, 44, 55})
+		registry.RegisterPlayer(2, []Number{11, 22, 33, 44, 66})
+		registry.BeReadyForProcessing()
+
+		report, err := ProcessLotteryPicksContext(context.Background(), registry, []Number{11, 22, 33, 44, 55})
+		assert.NoError(t, err)
+		assert.Equal(t, "0 0 1 1", report.String())
+		registry.ResetLastProcessing()
+	}
+}
+
+func TestProcessLotteryPicksContextAbortedByDeadline(t *testing.T) {
+	for _, registry := range []Registry{NewRegistry(), NewCompressedRegistry(0)} {
+		registry.RegisterPlayer(1, []Number{11, 22, 33, 44, 55})
+		registry.RegisterPlayer(2, []Number{11, 22, 33, 44, 66})
+		registry.BeReadyForProcessing()
+
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		_, err := ProcessLotteryPicksContext(ctx, registry, []Number{11, 22, 33, 44, 55})
+		cancel()
+		assert.ErrorIs(t, err, ErrDeadlineExceeded)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		//
+		// The registry is left reset, so the next processing is not affected by the aborted one.
+		//
+		report, err := ProcessLotteryPicksContext(context.Background(), registry, []Number{11, 22, 33, 44, 55})
+		assert.NoError(t, err)
+		assert.Equal(t, "0 0 1 1", report.String())
+	}
+}
+
+func TestProcessLotteryPicksContextCanceled(t *testing.T) {
+	registry := NewRegistry()
+	registry.RegisterPlayer(1, []Number{11, 22, 33, 44, 55})
+	registry.BeReadyForProcessing()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ProcessLotteryPicksContext(ctx, registry, []Number{11, 22, 33, 44, 55})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NotErrorIs(t, err, ErrDeadlineExceeded)
+}
+
+func TestContextError(t *testing.T) {
+	assert.NoError(t, ContextError(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	assert.ErrorIs(t, ContextError(ctx), ErrDeadlineExceeded)
+}
@@ -0,0 +1,95 @@
+package lottery
+
+// Dimension is a categorical attribute of the players, such as the source file they were loaded from. Each player
+// is assigned a label, for example "channel-a.txt", so that reports can be broken down by label.
+type Dimension struct {
+	Name string
+
+	labels []string
+	values map[string]uint16
+
+	//
+	// Stores the value of each player, where the player ID minus 1 is the index of the array, the same way as the
+	// registry does for counting matches. The value is the label index plus 1, so that 0 means unassigned.
+	// Using 16-bit values keeps the memory footprint at 2 bytes per player.
+	//
+	playerValues []uint16
+}
+
+// MaxLabels is the maximum number of distinct labels a [Dimension] can have.
+const MaxLabels = 1<<16 - 1
+
+// NewDimension creates a new [Dimension] without any players assigned to it.
+func NewDimension(name string) *Dimension {
+	return &Dimension{
+		Name:   name,
+		values: make(map[string]uint16),
+	}
+}
+
+// Accepts returns whether the label can be assigned, i.e., it is already known, or the dimension has room for one
+// more distinct label.
+func (d *Dimension) Accepts(label string) bool {
+	_, ok := d.values[label]
+	return ok || len(d.labels) < MaxLabels
+}
+
+// Assign assigns a label to a player. Returns false if the label could not be assigned, because the dimension
+// already has [MaxLabels] distinct labels.
+func (d *Dimension) Assign(playerID PlayerID, label string) bool {
+	return d.AssignRange(playerID, playerID, label)
+}
+
+// AssignRange assigns a label to all players from first to last, inclusive. Returns false if the label could not be
+// assigned, because the dimension already has [MaxLabels] distinct labels.
+func (d *Dimension) AssignRange(first PlayerID, last PlayerID, label string) bool {
+	value, ok := d.values[label]
+	if !ok {
+		if len(d.labels) == MaxLabels {
+			return false
+		}
+		d.labels = append(d.labels, label)
+		value = uint16(len(d.labels))
+		d.values[label] = value
+	}
+
+	if int(last) > len(d.playerValues) {
+		d.playerValues = append(d.playerValues, make([]uint16, int(last)-len(d.playerValues))...)
+	}
+	for playerID := first; playerID <= last; playerID++ {
+		d.playerValues[playerID-1] = value
+	}
+
+	return true
+}
+
+// LabelOf returns the label assigned to the player, or an empty string if unassigned.
+func (d *Dimension) LabelOf(playerID PlayerID) string {
+	index := int(playerID) - 1
+	if index < 0 || index >= len(d.playerValues) || d.playerValues[index] == 0 {
+		return ""
+	}
+	return d.labels[d.playerValues[index]-1]
+}
+
+// Labels returns all distinct labels, in the order they were first assigned.
+func (d *Dimension) Labels() []string {
+	return d.labels
+}
+
+// countPlayers counts the players having each label, out of the given number of players, those without a label under
+// an empty one. Labels without players are left out.
+func (d *Dimension) countPlayers(players int) map[string]int {
+	counts := make(map[string]int, len(d.labels)+1)
+	for _, value := range d.playerValues[:min(players, len(d.playerValues))] {
+		if value != 0 {
+			counts[d.labels[value-1]]++
+		} else {
+			counts[""]++
+		}
+	}
+	if players > len(d.playerValues) {
+		counts[""] += players - len(d.playerValues)
+	}
+	return counts
+}
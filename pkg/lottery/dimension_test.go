@@ -0,0 +1,41 @@
+package lottery
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDimensionAssignLabels(t *testing.T) {
+	dimension := NewDimension("region")
+
+	assert.True(t, dimension.Assign(3, "Pest"))
+	assert.True(t, dimension.AssignRange(5, 8, "Baranya"))
+	assert.True(t, dimension.Assign(7, "Pest"))
+
+	assert.Equal(t, "region", dimension.Name)
+	assert.Equal(t, []string{"Pest", "Baranya"}, dimension.Labels())
+
+	assert.Equal(t, "", dimension.LabelOf(1))
+	assert.Equal(t, "Pest", dimension.LabelOf(3))
+	assert.Equal(t, "", dimension.LabelOf(4))
+	assert.Equal(t, "Baranya", dimension.LabelOf(5))
+	assert.Equal(t, "Pest", dimension.LabelOf(7))
+	assert.Equal(t, "Baranya", dimension.LabelOf(8))
+	assert.Equal(t, "", dimension.LabelOf(9))
+	assert.Equal(t, "", dimension.LabelOf(0))
+}
+
+func TestDimensionFailIfTooManyLabels(t *testing.T) {
+	dimension := NewDimension("account")
+
+	for i := 0; i < MaxLabels; i++ {
+		assert.True(t, dimension.Assign(1, strconv.Itoa(i)))
+	}
+
+	assert.False(t, dimension.Accepts("one too many"))
+	assert.False(t, dimension.Assign(2, "one too many"))
+	assert.True(t, dimension.Accepts(strconv.Itoa(0)))
+	assert.True(t, dimension.Assign(2, strconv.Itoa(0)))
+}
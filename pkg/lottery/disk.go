@@ -0,0 +1,413 @@
+package lottery
+
+import (
+	"bufio"
+	"container/heap"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"unsafe"
+)
+
+// DefaultWindowSize is the default number of players whose matches are counted at a time by a disk registry, taking
+// one byte each, i.e., 16 MiB.
+const DefaultWindowSize = 1 << 24
+
+// bufferSize is the size of the buffer of each bucket file, both for writing and reading.
+const bufferSize = 1 << 16
+
+// playerIDSize is the size of each player ID in a bucket file, encoded as little-endian.
+const playerIDSize = int(unsafe.Sizeof(PlayerID(0)))
+
+type diskRegistry struct {
+	//
+	// The same bucket sort as the regular registry, except that each bucket is a file of player IDs, rather than an
+	// array. While registering, IDs are appended to a buffered writer for each bucket. While processing, only the
+	// buckets of the drawn numbers are read, sequentially.
+	//
+	directory string
+	files     [MaxNumber]*os.File
+	writers   [MaxNumber]*bufio.Writer
+	sizes     [MaxNumber]int
+
+	//
+	// Counting requires player IDs in ascending order within each bucket, which is how they are usually registered.
+	// Otherwise, the bucket is sorted once, before processing.
+	//
+	lastPlayerIDs [MaxNumber]PlayerID
+	unsorted      [MaxNumber]bool
+
+	maxPlayerID PlayerID
+
+	//
+	// Rather than a sparse array for all players, matches are counted for a window of consecutive player IDs at a
+	// time. Since the buckets are sorted, they are merged window by window in a single sequential pass, and memory
+	// is bounded by the size of the window, regardless of the number of players.
+	//
+	window []uint8
+
+	//
+	// Only the players having matches are found in the buckets of a draw, so the players of each window are counted
+	// while registering, in order to count those without any matches directly, window by window. Unlike the regular
+	// registry, a player ID registered twice cannot be told apart from two players.
+	//
+	windowPlayers []int
+
+	// err is the first I/O error, after which the registry is unusable.
+	err error
+}
+
+// NewDiskRegistry creates a new lottery registry for ticket volumes beyond memory. Buckets are kept in files, under
+// a new temporary directory within the given one, and matches are counted in windows of the given number of
+// players, or [DefaultWindowSize] if not positive. Reports are identical to the regular registry, but processing is
+// bounded by disk throughput.
+//
+// Since [Registry] methods do not return errors, the first I/O error is kept, returned by Err, by
+// [ProcessLotteryPicksContext], and by Close. Meanwhile, [Registry.ProcessLotteryPicks] returns an empty report,
+// which fails [CheckConsistency] unless there are no players, so its callers must check either one of them. Close
+// removes the files. It does not implement [MatchVisitor].
+func NewDiskRegistry(directory string, windowSize int) (Registry, error) {
+	if windowSize <= 0 {
+		windowSize = DefaultWindowSize
+	}
+
+	path, err := os.MkdirTemp(directory, "buckets-")
+	if err != nil {
+		return nil, err
+	}
+
+	r := &diskRegistry{directory: path, window: make([]uint8, windowSize)}
+	for i := range r.files {
+		if r.files[i], err = os.Create(filepath.Join(path, fmt.Sprintf("bucket-%02d", i+1))); err != nil {
+			_ = r.Close()
+			return nil, err
+		}
+		r.writers[i] = bufio.NewWriterSize(r.files[i], bufferSize)
+	}
+
+	return r, nil
+}
+
+func (r *diskRegistry) RegisterPlayer(playerID PlayerID, picks []Number) {
+	if r.err != nil {
+		return
+	}
+
+	var encoded [playerIDSize]byte
+	binary.LittleEndian.PutUint32(encoded[:], uint32(playerID))
+
+	for _, pick := range picks {
+		index := pick - 1
+		if _, err := r.writers[index].Write(encoded[:]); err != nil {
+			r.err = err
+			return
+		}
+		if playerID < r.lastPlayerIDs[index] {
+			r.unsorted[index] = true
+		}
+		r.lastPlayerIDs[index] = playerID
+		r.sizes[index]++
+	}
+
+	window := int(playerID-1) / len(r.window)
+	if window >= len(r.windowPlayers) {
+		r.windowPlayers = append(r.windowPlayers, make([]int, window-len(r.windowPlayers)+1)...)
+	}
+	r.windowPlayers[window]++
+	r.maxPlayerID = max(r.maxPlayerID, playerID)
+}
+
+func (r *diskRegistry) BeReadyForProcessing() {
+	if r.err != nil {
+		return
+	}
+
+	for i := range r.writers {
+		if err := r.writers[i].Flush(); err != nil {
+			r.err = err
+			return
+		}
+		r.writers[i] = nil
+
+		if r.unsorted[i] {
+			if err := r.sortBucket(i); err != nil {
+				r.err = err
+				return
+			}
+			r.unsorted[i] = false
+		}
+	}
+
+	runtime.GC()
+}
+
+// sortBucket sorts the player IDs of a bucket. A bucket which fits in the window, i.e., having up to as many player
+// IDs as the window has bytes over the size of each ID, is sorted in memory. Larger ones are sorted by runs of that
+// many IDs, written to a temporary file, then merged back into the bucket file, so that memory is bounded by the
+// window, plus a read buffer per run, regardless of the size of the bucket.
+func (r *diskRegistry) sortBucket(index int) error {
+	runLength := max(len(r.window)/playerIDSize, 1)
+	if r.sizes[index] <= runLength {
+		return sortRun(r.files[index], 0, r.sizes[index])
+	}
+
+	runs, err := os.CreateTemp(r.directory, "runs-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = runs.Close()
+		_ = os.Remove(runs.Name())
+	}()
+
+	var readers []*bucketReader
+	for start := 0; start < r.sizes[index]; start += runLength {
+		size := min(runLength, r.sizes[index]-start)
+		offset := int64(start * playerIDSize)
+
+		if _, err = io.Copy(io.NewOffsetWriter(runs, offset),
+			io.NewSectionReader(r.files[index], offset, int64(size*playerIDSize))); err != nil {
+			return err
+		}
+		if err = sortRun(runs, offset, size); err != nil {
+			return err
+		}
+
+		reader, err := newBucketReader(runs, offset, size)
+		if err != nil {
+			return err
+		}
+		readers = append(readers, reader)
+	}
+
+	return mergeRuns(readers, r.files[index])
+}
+
+// sortRun sorts, in memory, the given number of player IDs of a file, starting at the given offset.
+func sortRun(file *os.File, offset int64, size int) error {
+	encoded := make([]byte, size*playerIDSize)
+	if _, err := file.ReadAt(encoded, offset); err != nil {
+		return err
+	}
+
+	playerIDs := make([]PlayerID, size)
+	for i := range playerIDs {
+		playerIDs[i] = PlayerID(binary.LittleEndian.Uint32(encoded[i*playerIDSize:]))
+	}
+	slices.Sort(playerIDs)
+	for i, playerID := range playerIDs {
+		binary.LittleEndian.PutUint32(encoded[i*playerIDSize:], uint32(playerID))
+	}
+
+	_, err := file.WriteAt(encoded, offset)
+	return err
+}
+
+// mergeRuns merges the player IDs of sorted runs into the given file, from its beginning, in ascending order.
+func mergeRuns(readers []*bucketReader, file *os.File) error {
+	writer := bufio.NewWriterSize(io.NewOffsetWriter(file, 0), bufferSize)
+	runs := runHeap(readers)
+	heap.Init(&runs)
+
+	var encoded [playerIDSize]byte
+	for len(runs) > 0 {
+		reader := runs[0]
+		binary.LittleEndian.PutUint32(encoded[:], uint32(reader.next))
+		if _, err := writer.Write(encoded[:]); err != nil {
+			return err
+		}
+
+		if err := reader.advance(); err != nil {
+			return err
+		}
+		if reader.remaining > 0 {
+			heap.Fix(&runs, 0)
+		} else {
+			heap.Pop(&runs)
+		}
+	}
+
+	return writer.Flush()
+}
+
+// runHeap is a min-heap of the readers of sorted runs, by their next player ID, implementing [heap.Interface].
+type runHeap []*bucketReader
+
+func (h runHeap) Len() int           { return len(h) }
+func (h runHeap) Less(i, j int) bool { return h[i].next < h[j].next }
+func (h runHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *runHeap) Push(x any)        { *h = append(*h, x.(*bucketReader)) }
+
+func (h *runHeap) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+func (r *diskRegistry) ProcessLotteryPicks(picks []Number) Report {
+	report, err := r.ProcessLotteryPicksContext(context.Background(), picks)
+	if err != nil {
+		return NewReport()
+	}
+	return report
+}
+
+func (r *diskRegistry) ProcessLotteryPicksContext(ctx context.Context, picks []Number) (Report, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	readers := make([]*bucketReader, 0, len(picks))
+	for _, pick := range picks {
+		index := pick - 1
+		reader, err := newBucketReader(r.files[index], 0, r.sizes[index])
+		if err != nil {
+			return nil, r.fail(err)
+		}
+		readers = append(readers, reader)
+	}
+
+	report := newReport(NumPicks)
+	windowSize := PlayerID(len(r.window))
+
+	for low := PlayerID(1); low <= r.maxPlayerID; low += windowSize {
+		if err := ContextError(ctx); err != nil {
+			r.ResetLastProcessing()
+			return nil, err
+		}
+
+		//
+		// Counts the matches of all players within the window, from the buckets of all drawn numbers, then
+		// tallies and resets the window for the next one.
+		//
+		high := low + min(windowSize, r.maxPlayerID-low+1)
+		for _, reader := range readers {
+			for reader.remaining > 0 && reader.next < high {
+				r.window[reader.next-low]++
+				if err := reader.advance(); err != nil {
+					return nil, r.fail(err)
+				}
+			}
+		}
+
+		tallied := 0
+		for i, count := range r.window[:high-low] {
+			if count > 0 {
+				report.IncrementWinnersHaving(int(count))
+				r.window[i] = 0
+				tallied++
+			}
+		}
+		report.AddWinnersHaving(0, r.windowPlayers[int(low-1)/len(r.window)]-tallied)
+	}
+
+	return report, nil
+}
+
+// fail keeps the first I/O error while processing, after which the registry is unusable, resetting the counts of
+// the window it was aborted at.
+func (r *diskRegistry) fail(err error) error {
+	r.err = err
+	r.ResetLastProcessing()
+	return err
+}
+
+func (r *diskRegistry) ResetLastProcessing() {
+	//
+	// Windows are reset as they are tallied, so only an aborted processing leaves counts behind.
+	//
+	clear(r.window)
+}
+
+func (r *diskRegistry) HasPlayerPick(playerID PlayerID, pick Number) bool {
+	index := pick - 1
+	if r.writers[index] != nil && r.writers[index].Flush() != nil {
+		return false
+	}
+
+	reader, err := newBucketReader(r.files[index], 0, r.sizes[index])
+	if err != nil {
+		return false
+	}
+	for reader.remaining > 0 {
+		if reader.next == playerID {
+			return true
+		}
+		if reader.advance() != nil {
+			return false
+		}
+	}
+	return false
+}
+
+func (r *diskRegistry) BucketSizes() []int {
+	return slices.Clone(r.sizes[:])
+}
+
+func (r *diskRegistry) MemoryFootprint() int {
+	footprint := cap(r.window)
+	for _, writer := range r.writers {
+		if writer != nil {
+			footprint += writer.Size()
+		}
+	}
+	return footprint
+}
+
+// Err returns the first I/O error of the registry, if any.
+func (r *diskRegistry) Err() error {
+	return r.err
+}
+
+// Close closes and removes the bucket files, returning the first I/O error of the registry, if any.
+func (r *diskRegistry) Close() error {
+	errs := []error{r.err}
+	for _, file := range r.files {
+		if file != nil {
+			errs = append(errs, file.Close())
+		}
+	}
+	errs = append(errs, os.RemoveAll(r.directory))
+	return errors.Join(errs...)
+}
+
+// bucketReader reads the player IDs of a bucket file sequentially, holding the next one.
+type bucketReader struct {
+	reader    *bufio.Reader
+	remaining int
+	next      PlayerID
+}
+
+// newBucketReader reads the given number of player IDs of a file, starting at the given offset.
+func newBucketReader(file *os.File, offset int64, size int) (*bucketReader, error) {
+	reader := &bucketReader{
+		reader:    bufio.NewReaderSize(io.NewSectionReader(file, offset, int64(size*playerIDSize)), bufferSize),
+		remaining: size + 1,
+	}
+	if err := reader.advance(); err != nil {
+		return nil, err
+	}
+	return reader, nil
+}
+
+// advance reads the next player ID, if any remains.
+func (b *bucketReader) advance() error {
+	b.remaining--
+	if b.remaining == 0 {
+		return nil
+	}
+
+	var encoded [playerIDSize]byte
+	if _, err := io.ReadFull(b.reader, encoded[:]); err != nil {
+		return err
+	}
+	b.next = PlayerID(binary.LittleEndian.Uint32(encoded[:]))
+	return nil
+}
@@ -0,0 +1,179 @@
+package lottery
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiskRegistryNoPlayerPicksGiven(t *testing.T) {
+	registry := newDiskRegistry(t, 0)
+	registry.BeReadyForProcessing()
+
+	report := registry.ProcessLotteryPicks([]Number{11, 22, 33, 44, 55})
+
+	assert.Equal(t, "0 0 0 0", report.String())
+}
+
+func TestDiskRegistryCountsMatchesAcrossWindows(t *testing.T) {
+	registry := newDiskRegistry(t, 3)
+
+	registry.RegisterPlayer(1, []Number{11, 22, 33, 44, 55})
+	registry.RegisterPlayer(2, []Number{55, 44, 33, 22, 11})
+	registry.RegisterPlayer(3, []Number{44, 33, 22, 11, 88})
+	registry.RegisterPlayer(4, []Number{33, 22, 11, 87, 88})
+	registry.RegisterPlayer(5, []Number{88, 11, 22, 33, 87})
+	registry.RegisterPlayer(6, []Number{22, 11, 86, 87, 88})
+	registry.RegisterPlayer(7, []Number{11, 85, 86, 87, 88})
+	registry.BeReadyForProcessing()
+
+	assert.True(t, registry.HasPlayerPick(4, 87))
+	assert.False(t, registry.HasPlayerPick(4, 55))
+
+	report := registry.ProcessLotteryPicks([]Number{11, 22, 33, 44, 55})
+
+	assert.Equal(t, "1 2 1 2", report.String())
+	registry.ResetLastProcessing()
+
+	report = registry.ProcessLotteryPicks([]Number{85, 86, 87, 88, 90})
+
+	assert.Equal(t, "2 1 1 0", report.String())
+}
+
+func TestDiskRegistrySortsPlayersRegisteredOutOfOrder(t *testing.T) {
+	registry := newDiskRegistry(t, 2)
+
+	registry.RegisterPlayer(9, []Number{1, 2, 3, 4, 5})
+	registry.RegisterPlayer(3, []Number{1, 2, 3, 4, 6})
+	registry.RegisterPlayer(6, []Number{1, 2, 3, 7, 8})
+	registry.BeReadyForProcessing()
+
+	report := registry.ProcessLotteryPicks([]Number{1, 2, 3, 4, 5})
+
+	assert.Equal(t, "0 1 1 1", report.String())
+}
+
+func TestDiskRegistrySortsBucketsLargerThanTheWindow(t *testing.T) {
+	directory := t.TempDir()
+	registry, err := NewDiskRegistry(directory, 3*playerIDSize)
+	assert.NoError(t, err)
+	defer func() { _ = registry.(io.Closer).Close() }()
+
+	//
+	// Every bucket of the drawn numbers holds 1000 players, in descending order, far more than the 3 player IDs that
+	// fit in the window, so these are sorted by runs, then merged.
+	//
+	random := rand.New(rand.NewSource(42))
+	regular := NewRegistry()
+	for playerID := PlayerID(1000); playerID >= 1; playerID-- {
+		picks := []Number{1, 2}
+		for _, n := range random.Perm(28)[:NumPicks-2] {
+			picks = append(picks, Number(n+3))
+		}
+		regular.RegisterPlayer(playerID, picks)
+		registry.RegisterPlayer(playerID, picks)
+	}
+	regular.BeReadyForProcessing()
+	registry.BeReadyForProcessing()
+	assert.NoError(t, registry.(interface{ Err() error }).Err())
+
+	for draw := 0; draw < 20; draw++ {
+		picks := randomPicks(random, 30)
+		assert.Equal(t, regular.ProcessLotteryPicks(picks), registry.ProcessLotteryPicks(picks))
+		regular.ResetLastProcessing()
+		registry.ResetLastProcessing()
+	}
+
+	assert.True(t, registry.HasPlayerPick(1, 1))
+	assert.True(t, registry.HasPlayerPick(1000, 2))
+
+	//
+	// The runs are removed once merged, leaving only the buckets.
+	//
+	entries, err := os.ReadDir(registry.(*diskRegistry).directory)
+	assert.NoError(t, err)
+	assert.Len(t, entries, MaxNumber)
+}
+
+func TestDiskRegistryMatchesRegularRegistry(t *testing.T) {
+	random := rand.New(rand.NewSource(42))
+	regular := NewRegistry()
+	disk := newDiskRegistry(t, 1000)
+
+	for playerID := PlayerID(1); playerID <= 5000; playerID++ {
+		picks := randomPicks(random, 30)
+		regular.RegisterPlayer(playerID, picks)
+		disk.RegisterPlayer(playerID, picks)
+	}
+	regular.BeReadyForProcessing()
+	disk.BeReadyForProcessing()
+
+	for draw := 0; draw < 20; draw++ {
+		picks := randomPicks(random, 30)
+		assert.Equal(t, regular.ProcessLotteryPicks(picks), disk.ProcessLotteryPicks(picks))
+		regular.ResetLastProcessing()
+		disk.ResetLastProcessing()
+	}
+}
+
+func TestDiskRegistryAbortedProcessing(t *testing.T) {
+	registry := newDiskRegistry(t, 0)
+	registry.RegisterPlayer(1, []Number{11, 22, 33, 44, 55})
+	registry.BeReadyForProcessing()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ProcessLotteryPicksContext(ctx, registry, []Number{11, 22, 33, 44, 55})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	report, err := ProcessLotteryPicksContext(context.Background(), registry, []Number{11, 22, 33, 44, 55})
+	assert.NoError(t, err)
+	assert.Equal(t, "0 0 0 1", report.String())
+}
+
+func TestDiskRegistryFailsOnReadError(t *testing.T) {
+	registry := newDiskRegistry(t, 100)
+	for playerID := PlayerID(1); playerID <= 10; playerID++ {
+		registry.RegisterPlayer(playerID, []Number{11, 22, 33, 44, 55})
+	}
+	registry.BeReadyForProcessing()
+
+	//
+	// The bucket of 11 is cut in half, so reading fails in the middle of the window.
+	//
+	disk := registry.(*diskRegistry)
+	assert.NoError(t, disk.files[10].Truncate(int64(5*playerIDSize)))
+
+	_, err := ProcessLotteryPicksContext(context.Background(), registry, []Number{11, 22, 33, 44, 55})
+	assert.Error(t, err)
+	assert.Equal(t, make([]uint8, 100), disk.window)
+	assert.ErrorIs(t, disk.Err(), err)
+
+	report := registry.ProcessLotteryPicks([]Number{11, 22, 33, 44, 55})
+	assert.ErrorIs(t, CheckConsistency(report, 10), ErrInconsistentReport)
+}
+
+func TestDiskRegistryRemovesFilesOnClose(t *testing.T) {
+	directory := t.TempDir()
+	registry, err := NewDiskRegistry(directory, 0)
+	assert.NoError(t, err)
+	registry.RegisterPlayer(1, []Number{11, 22, 33, 44, 55})
+
+	assert.NoError(t, registry.(io.Closer).Close())
+
+	entries, err := os.ReadDir(directory)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func newDiskRegistry(t *testing.T, windowSize int) Registry {
+	registry, err := NewDiskRegistry(t.TempDir(), windowSize)
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = registry.(io.Closer).Close() })
+	return registry
+}
@@ -0,0 +1,44 @@
+package lottery
+
+import "context"
+
+// DualReport combines the reports of two draws processed against the same tickets, such as the machine and manual
+// draws of Skandináv lottó, whose prizes are computed per draw.
+type DualReport struct {
+	Machine Report
+	Manual  Report
+}
+
+// String formats the report of each draw, machine draw first, separated by a vertical bar, eg: "0 1 0 0 0 0 | 1 0 0
+// 0 0 0".
+func (r DualReport) String() string {
+	return r.Machine.String() + " | " + r.Manual.String()
+}
+
+// ProcessDualDraw processes both the machine and the manual draw against the registry, in a single call. See
+// [ProcessDualDrawContext].
+func ProcessDualDraw(registry Registry, machine []Number, manual []Number) DualReport {
+	report, _ := ProcessDualDrawContext(context.Background(), registry, machine, manual)
+	return report
+}
+
+// ProcessDualDrawContext processes the machine draw, then the manual draw, against the registry, giving up as soon as
+// the context is done, as [ProcessLotteryPicksContext] does. The report of the machine draw is built before the
+// registry is reset for the manual draw, so that neither result is lost. As with [Registry.ProcessLotteryPicks], the
+// registry must be reset afterwards, since it holds the last processing, i.e., the one of the manual draw.
+func ProcessDualDrawContext(ctx context.Context, registry Registry, machine []Number, manual []Number) (
+	DualReport, error,
+) {
+	var report DualReport
+	var err error
+
+	if report.Machine, err = ProcessLotteryPicksContext(ctx, registry, machine); err != nil {
+		return DualReport{}, err
+	}
+	registry.ResetLastProcessing()
+
+	if report.Manual, err = ProcessLotteryPicksContext(ctx, registry, manual); err != nil {
+		return DualReport{}, err
+	}
+	return report, nil
+}
@@ -0,0 +1,49 @@
+package lottery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessDualDraw(t *testing.T) {
+	registry := NewGameRegistry(Skandinav, nil)
+	registry.RegisterPlayer(1, []Number{1, 2, 3, 4, 5, 6, 7})
+	registry.RegisterPlayer(2, []Number{1, 2, 3, 29, 30, 31, 32})
+	registry.RegisterPlayer(3, []Number{8, 9, 10, 11, 12, 13, 14})
+	registry.BeReadyForProcessing()
+
+	report := ProcessDualDraw(registry, []Number{1, 2, 3, 4, 5, 6, 7}, []Number{8, 9, 10, 29, 30, 31, 35})
+	registry.ResetLastProcessing()
+
+	assert.Equal(t, 1, report.Machine.GetWinnersHaving(7))
+	assert.Equal(t, 1, report.Machine.GetWinnersHaving(3))
+	assert.Equal(t, 0, report.Machine.GetWinnersHaving(2))
+	assert.Equal(t, 2, report.Manual.GetWinnersHaving(3))
+	assert.Equal(t, 0, report.Manual.GetWinnersHaving(7))
+	assert.Equal(t, "0 1 0 0 0 1 | 0 2 0 0 0 0", report.String())
+
+	//
+	// Both draws are processed from scratch, so the same draw twice yields the same report twice.
+	//
+	report = ProcessDualDraw(registry, []Number{1, 2, 3, 4, 5, 6, 7}, []Number{1, 2, 3, 4, 5, 6, 7})
+	registry.ResetLastProcessing()
+	assert.Equal(t, "0 1 0 0 0 1 | 0 1 0 0 0 1", report.String())
+}
+
+func TestProcessDualDrawContextAbortedByDeadline(t *testing.T) {
+	registry := NewGameRegistry(Skandinav, nil)
+	registry.RegisterPlayer(1, []Number{1, 2, 3, 4, 5, 6, 7})
+	registry.BeReadyForProcessing()
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	_, err := ProcessDualDrawContext(ctx, registry, []Number{1, 2, 3, 4, 5, 6, 7}, []Number{1, 2, 3, 4, 5, 6, 7})
+	assert.ErrorIs(t, err, ErrDeadlineExceeded)
+
+	report := ProcessDualDraw(registry, []Number{1, 2, 3, 4, 5, 6, 7}, []Number{1, 2, 8, 9, 10, 11, 12})
+	registry.ResetLastProcessing()
+	assert.Equal(t, "0 0 0 0 0 1 | 1 0 0 0 0 0", report.String())
+}
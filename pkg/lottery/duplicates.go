@@ -0,0 +1,153 @@
+package lottery
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// CombinationIndex counts how many tickets picked each combination. Many tickets share the same combination, for
+// example "1 2 3 4 5", so this allows determining in O(1) how many tickets exactly equal the drawn combination, i.e.,
+// how many winners share the jackpot, as well as finding the most popular combinations, and suspicious mass
+// duplicates coming from a single source.
+type CombinationIndex struct {
+	counts map[Combination]int
+
+	// sources interns the source labels, so that counts per source can be keyed by a small number.
+	sources      []string
+	sourceValues map[string]uint16
+	sourceCounts map[sourceCombination]int
+}
+
+type sourceCombination struct {
+	combination Combination
+	source      uint16
+}
+
+// CombinationCount is the number of tickets that picked a combination.
+type CombinationCount struct {
+	Combination Combination
+	Count       int
+}
+
+// SourceDuplicate is the number of identical tickets, picking the same combination, coming from a single source.
+type SourceDuplicate struct {
+	Combination Combination
+	Source      string
+	Count       int
+}
+
+// NewCombinationIndex creates a new, empty, [CombinationIndex].
+func NewCombinationIndex() *CombinationIndex {
+	return &CombinationIndex{
+		counts:       make(map[Combination]int),
+		sourceValues: make(map[string]uint16),
+		sourceCounts: make(map[sourceCombination]int),
+	}
+}
+
+// Add counts a ticket with the given picks, coming from the given source. The source is optional, and may be empty.
+// Up to [MaxLabels] distinct sources are tracked; tickets from further sources are only counted in total.
+func (x *CombinationIndex) Add(picks []Number, source string) {
+	combination := CombinationOf(picks)
+	x.counts[combination]++
+
+	if source == "" {
+		return
+	}
+
+	value, ok := x.sourceValues[source]
+	if !ok {
+		if len(x.sources) == MaxLabels {
+			return
+		}
+		value = uint16(len(x.sources))
+		x.sources = append(x.sources, source)
+		x.sourceValues[source] = value
+	}
+	x.sourceCounts[sourceCombination{combination: combination, source: value}]++
+}
+
+// Count returns how many tickets picked exactly the same combination as the given picks, in any order.
+func (x *CombinationIndex) Count(picks []Number) int {
+	return x.counts[CombinationOf(picks)]
+}
+
+// Distinct returns how many distinct combinations were picked.
+func (x *CombinationIndex) Distinct() int {
+	return len(x.counts)
+}
+
+// MostPopular returns, at most, the n combinations picked by the most tickets, from the most to the least popular.
+// Ties are broken by the lowest combination.
+func (x *CombinationIndex) MostPopular(n int) []CombinationCount {
+	//
+	// Keeps the n most popular combinations in a min-heap, so that finding them takes O(m log n) for m distinct
+	// combinations, instead of sorting all of them.
+	//
+	popular := &combinationHeap{}
+	for combination, count := range x.counts {
+		candidate := CombinationCount{Combination: combination, Count: count}
+		if popular.Len() < n {
+			heap.Push(popular, candidate)
+		} else if n > 0 && popular.less(popular.items[0], candidate) {
+			popular.items[0] = candidate
+			heap.Fix(popular, 0)
+		}
+	}
+
+	result := make([]CombinationCount, popular.Len())
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = heap.Pop(popular).(CombinationCount)
+	}
+	return result
+}
+
+// SuspiciousDuplicates returns all combinations picked by, at least, threshold tickets coming from a single source,
+// from the most to the least duplicated.
+func (x *CombinationIndex) SuspiciousDuplicates(threshold int) []SourceDuplicate {
+	var duplicates []SourceDuplicate
+	for key, count := range x.sourceCounts {
+		if count >= threshold {
+			duplicates = append(duplicates, SourceDuplicate{
+				Combination: key.combination,
+				Source:      x.sources[key.source],
+				Count:       count,
+			})
+		}
+	}
+
+	sort.Slice(duplicates, func(i, j int) bool {
+		if duplicates[i].Count != duplicates[j].Count {
+			return duplicates[i].Count > duplicates[j].Count
+		}
+		if duplicates[i].Source != duplicates[j].Source {
+			return duplicates[i].Source < duplicates[j].Source
+		}
+		return duplicates[i].Combination < duplicates[j].Combination
+	})
+
+	return duplicates
+}
+
+// combinationHeap is a min-heap of combination counts, where the least popular combination is at the top.
+type combinationHeap struct {
+	items []CombinationCount
+}
+
+func (h *combinationHeap) less(a CombinationCount, b CombinationCount) bool {
+	if a.Count != b.Count {
+		return a.Count < b.Count
+	}
+	return a.Combination > b.Combination
+}
+
+func (h *combinationHeap) Len() int           { return len(h.items) }
+func (h *combinationHeap) Less(i, j int) bool { return h.less(h.items[i], h.items[j]) }
+func (h *combinationHeap) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *combinationHeap) Push(x any)         { h.items = append(h.items, x.(CombinationCount)) }
+
+func (h *combinationHeap) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
@@ -0,0 +1,69 @@
+package lottery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCombinationIndexCount(t *testing.T) {
+	index := NewCombinationIndex()
+
+	index.Add([]Number{1, 2, 3, 4, 5}, "")
+	index.Add([]Number{5, 4, 3, 2, 1}, "")
+	index.Add([]Number{3, 1, 2, 5, 4}, "")
+	index.Add([]Number{11, 22, 33, 44, 55}, "")
+
+	assert.Equal(t, 3, index.Count([]Number{1, 2, 3, 4, 5}))
+	assert.Equal(t, 1, index.Count([]Number{55, 44, 33, 22, 11}))
+	assert.Equal(t, 0, index.Count([]Number{1, 2, 3, 4, 6}))
+	assert.Equal(t, 2, index.Distinct())
+}
+
+func TestCombinationIndexMostPopular(t *testing.T) {
+	index := NewCombinationIndex()
+
+	for i := 0; i < 5; i++ {
+		index.Add([]Number{1, 2, 3, 4, 5}, "")
+	}
+	for i := 0; i < 3; i++ {
+		index.Add([]Number{7, 14, 21, 28, 35}, "")
+	}
+	for i := 0; i < 3; i++ {
+		index.Add([]Number{6, 12, 18, 24, 30}, "")
+	}
+	index.Add([]Number{11, 22, 33, 44, 55}, "")
+
+	popular := index.MostPopular(3)
+	assert.Equal(t, []CombinationCount{
+		{Combination: CombinationOf([]Number{1, 2, 3, 4, 5}), Count: 5},
+		{Combination: CombinationOf([]Number{6, 12, 18, 24, 30}), Count: 3},
+		{Combination: CombinationOf([]Number{7, 14, 21, 28, 35}), Count: 3},
+	}, popular)
+
+	assert.Len(t, index.MostPopular(10), 4)
+	assert.Empty(t, index.MostPopular(0))
+}
+
+func TestCombinationIndexSuspiciousDuplicates(t *testing.T) {
+	index := NewCombinationIndex()
+
+	for i := 0; i < 50; i++ {
+		index.Add([]Number{1, 2, 3, 4, 5}, "channel-a.txt")
+	}
+	for i := 0; i < 60; i++ {
+		index.Add([]Number{1, 2, 3, 4, 5}, "channel-b.txt")
+	}
+	for i := 0; i < 200; i++ {
+		index.Add([]Number{10, 20, 30, 40, 50}, "channel-c.txt")
+	}
+	index.Add([]Number{10, 20, 30, 40, 50}, "channel-a.txt")
+
+	assert.Equal(t, []SourceDuplicate{
+		{Combination: CombinationOf([]Number{10, 20, 30, 40, 50}), Source: "channel-c.txt", Count: 200},
+		{Combination: CombinationOf([]Number{1, 2, 3, 4, 5}), Source: "channel-b.txt", Count: 60},
+	}, index.SuspiciousDuplicates(60))
+
+	assert.Equal(t, 110, index.Count([]Number{1, 2, 3, 4, 5}))
+	assert.Len(t, index.SuspiciousDuplicates(1), 4)
+}
@@ -0,0 +1,37 @@
+package lottery
+
+// Game holds the rules of a lottery game: how many distinct numbers are picked, from 1 up to the maximum number,
+// both by players and by the draw. For all games, the maximum number is at most [MaxNumber].
+type Game struct {
+	Name      string
+	NumPicks  int
+	MaxNumber int
+
+	// Dual tells whether each drawing has two draws against the same tickets, a machine and a manual one. See
+	// [ProcessDualDraw].
+	Dual bool
+}
+
+var (
+	// Otoslotto is the Hungarian lottery, picking 5 numbers out of 90, which is the default game.
+	Otoslotto = Game{Name: "otoslotto", NumPicks: NumPicks, MaxNumber: MaxNumber}
+
+	// Hatoslotto picks 6 numbers out of 45.
+	Hatoslotto = Game{Name: "hatoslotto", NumPicks: 6, MaxNumber: 45}
+
+	// Skandinav picks 7 numbers out of 35, drawn twice, by machine and manually.
+	Skandinav = Game{Name: "skandinav", NumPicks: 7, MaxNumber: 35, Dual: true}
+)
+
+// Games are all supported games.
+var Games = []Game{Otoslotto, Hatoslotto, Skandinav}
+
+// LookupGame returns the supported game with the given name, if any.
+func LookupGame(name string) (Game, bool) {
+	for _, game := range Games {
+		if game.Name == name {
+			return game, true
+		}
+	}
+	return Game{}, false
+}
@@ -0,0 +1,37 @@
+package lottery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupGame(t *testing.T) {
+	game, ok := LookupGame("hatoslotto")
+	assert.True(t, ok)
+	assert.Equal(t, Hatoslotto, game)
+
+	_, ok = LookupGame("keno")
+	assert.False(t, ok)
+
+	for _, game := range Games {
+		assert.LessOrEqual(t, game.MaxNumber, MaxNumber, game.Name)
+	}
+}
+
+func TestGameRegistry(t *testing.T) {
+	registry := NewGameRegistry(Skandinav, nil)
+
+	registry.RegisterPlayer(1, []Number{1, 2, 3, 4, 5, 6, 7})
+	registry.RegisterPlayer(2, []Number{1, 2, 3, 4, 5, 6, 35})
+	registry.RegisterPlayer(3, []Number{1, 2, 30, 31, 32, 33, 34})
+	registry.BeReadyForProcessing()
+
+	report := registry.ProcessLotteryPicks([]Number{1, 2, 3, 4, 5, 6, 7})
+
+	assert.Equal(t, "1 0 0 0 1 1", report.String())
+	assert.Equal(t, 1, report.GetWinnersHaving(7))
+	assert.Equal(t, 1, report.GetWinnersHaving(6))
+	assert.Equal(t, 1, report.GetWinnersHaving(2))
+	assert.Equal(t, 0, report.GetWinnersHaving(8))
+}
@@ -0,0 +1,47 @@
+package lottery
+
+import "unsafe"
+
+// Inspector is implemented by registries that can describe their internal state, for monitoring.
+type Inspector interface {
+
+	// BucketSizes returns how many entries the bucket of each number holds, where the number minus 1 is the index.
+	BucketSizes() []int
+
+	// MemoryFootprint estimates the memory taken by the registry, in bytes, from the capacity of its arrays.
+	MemoryFootprint() int
+}
+
+func (r *registry) BucketSizes() []int {
+	sizes := make([]int, len(r.buckets))
+	for i, bucket := range r.buckets {
+		sizes[i] = len(bucket)
+	}
+	return sizes
+}
+
+func (r *registry) MemoryFootprint() int {
+	footprint := cap(r.playerMatches) * int(unsafe.Sizeof(int(0)))
+	for _, bucket := range r.buckets {
+		footprint += cap(bucket) * int(unsafe.Sizeof(PlayerID(0)))
+	}
+	return footprint
+}
+
+func (r *compressedRegistry) BucketSizes() []int {
+	sizes := make([]int, MaxNumber)
+	for i, bucket := range r.buckets {
+		sizes[i] = len(bucket)
+	}
+	return sizes
+}
+
+func (r *compressedRegistry) MemoryFootprint() int {
+	footprint := (cap(r.players) + cap(r.combinations)) * int(unsafe.Sizeof(Combination(0)))
+	footprint += cap(r.multiplicities) * int(unsafe.Sizeof(int(0)))
+	footprint += cap(r.matches)
+	for _, bucket := range r.buckets {
+		footprint += cap(bucket) * int(unsafe.Sizeof(int32(0)))
+	}
+	return footprint
+}
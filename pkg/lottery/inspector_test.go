@@ -0,0 +1,41 @@
+package lottery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInspectRegistry(t *testing.T) {
+	allocation := make([]int, MaxNumber)
+	for _, pick := range []Number{11, 22, 33, 44, 55, 11, 22, 33, 44, 66} {
+		allocation[pick-1]++
+	}
+	registry := NewRegistryFromNumberAllocation(allocation)
+	registry.RegisterPlayer(1, []Number{11, 22, 33, 44, 55})
+	registry.RegisterPlayer(2, []Number{11, 22, 33, 44, 66})
+	registry.BeReadyForProcessing()
+
+	inspector := registry.(Inspector)
+	sizes := inspector.BucketSizes()
+	assert.Len(t, sizes, MaxNumber)
+	assert.Equal(t, 2, sizes[10])
+	assert.Equal(t, 1, sizes[54])
+	assert.Equal(t, 0, sizes[0])
+	assert.Equal(t, 10*4+2*8, inspector.MemoryFootprint())
+}
+
+func TestInspectCompressedRegistry(t *testing.T) {
+	registry := NewCompressedRegistry(3)
+	registry.RegisterPlayer(1, []Number{11, 22, 33, 44, 55})
+	registry.RegisterPlayer(2, []Number{55, 44, 33, 22, 11})
+	registry.RegisterPlayer(3, []Number{11, 22, 33, 44, 66})
+	registry.BeReadyForProcessing()
+
+	inspector := registry.(Inspector)
+	sizes := inspector.BucketSizes()
+	assert.Equal(t, 2, sizes[10])
+	assert.Equal(t, 1, sizes[54])
+	assert.Equal(t, 1, sizes[65])
+	assert.Greater(t, inspector.MemoryFootprint(), 0)
+}
@@ -0,0 +1,173 @@
+package lottery
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const (
+	// KenoMinPicks is the fewest numbers a Kenó ticket may pick.
+	KenoMinPicks = 1
+
+	// KenoMaxPicks is the most numbers a Kenó ticket may pick.
+	KenoMaxPicks = 10
+
+	// KenoMaxNumber is the maximum Kenó number, inclusive.
+	KenoMaxNumber = 80
+)
+
+// Keno holds the rules of the draws of Hungarian Kenó, which picks 20 numbers out of 80. Unlike the draws, tickets
+// pick from [KenoMinPicks] up to [KenoMaxPicks] numbers, so they are registered into a [KenoRegistry] rather than a
+// [Registry]. For this reason, it is not one of the [Games].
+var Keno = Game{Name: "keno", NumPicks: 20, MaxNumber: KenoMaxNumber}
+
+// KenoRegistry registers the Kenó players and their picks, of any ticket size, and processes the Kenó draws. It works
+// the same way as [Registry] does, except that prize tiers depend on both the ticket size and the number of hits.
+type KenoRegistry interface {
+
+	// RegisterPlayer registers a player and its numeric picks, from [KenoMinPicks] up to [KenoMaxPicks] numbers, all
+	// of them from 1 up to the maximum number of [Keno]. The playerID is a unique, positive number, ideally
+	// sequential starting from 1.
+	RegisterPlayer(playerID PlayerID, picks []Number)
+
+	// BeReadyForProcessing carries optimizations necessary for correct and efficient processing of Kenó draws.
+	BeReadyForProcessing()
+
+	// ProcessKenoDraw processes the numbers of a Kenó draw, and returns a [KenoReport].
+	ProcessKenoDraw(draw []Number) KenoReport
+
+	// ResetLastProcessing resets the state of this registry from the last processing, the same way as
+	// [Registry.ResetLastProcessing] does. It MUST be invoked before processing a new draw.
+	ResetLastProcessing()
+
+	// HasPlayerPick determines if the player ID has picked the given number. Used for testing.
+	HasPlayerPick(playerID PlayerID, pick Number) bool
+}
+
+// KenoReport counts the Kenó tickets of each ticket size by their number of hits, from none up to all of their picks,
+// since prizes depend on both, and some ticket sizes win even without any hits.
+type KenoReport interface {
+
+	// IncrementTicketsHaving increments the number of tickets of the given size having the given number of hits.
+	// Sizes and hits out of range are ignored.
+	IncrementTicketsHaving(size int, hits int)
+
+	// GetTicketsHaving returns the number of tickets of the given size having the given number of hits.
+	GetTicketsHaving(size int, hits int) int
+
+	// String formats the report for textual representation: one group per ticket size, from the smallest, separated
+	// by vertical bars. Each group counts the tickets from no hits up to all of them, separated by spaces.
+	String() string
+}
+
+type kenoReport struct {
+	// tickets counts, for each ticket size minus 1, the tickets having from 0 up to size hits.
+	tickets [KenoMaxPicks][]int
+}
+
+// NewKenoReport creates a new, empty [KenoReport].
+func NewKenoReport() KenoReport {
+	var report kenoReport
+	for i := range report.tickets {
+		report.tickets[i] = make([]int, i+2)
+	}
+	return &report
+}
+
+func (r *kenoReport) IncrementTicketsHaving(size int, hits int) {
+	if size >= KenoMinPicks && size <= KenoMaxPicks && hits >= 0 && hits <= size {
+		r.tickets[size-1][hits]++
+	}
+}
+
+func (r *kenoReport) GetTicketsHaving(size int, hits int) int {
+	if size >= KenoMinPicks && size <= KenoMaxPicks && hits >= 0 && hits <= size {
+		return r.tickets[size-1][hits]
+	}
+	return 0
+}
+
+func (r *kenoReport) String() string {
+	groups := make([]string, len(r.tickets))
+	for i, counts := range r.tickets {
+		fields := make([]string, len(counts))
+		for hits, count := range counts {
+			fields[hits] = strconv.Itoa(count)
+		}
+		groups[i] = strings.Join(fields, " ")
+	}
+	return strings.Join(groups, " | ")
+}
+
+type kenoRegistry struct {
+	//
+	// Same as the regular registry, there is one bucket per number, holding the players who picked it, and a
+	// sparse array counting the hits of each player.
+	//
+	buckets [KenoMaxNumber]bucketType
+
+	// ticketSizes holds the number of picks of each player, where the player ID minus 1 is the index of the array,
+	// or zero if there is no such player.
+	ticketSizes []uint8
+
+	playerHits []uint8
+}
+
+// NewKenoRegistry creates a new, empty [KenoRegistry].
+func NewKenoRegistry() KenoRegistry {
+	return &kenoRegistry{}
+}
+
+func (r *kenoRegistry) RegisterPlayer(playerID PlayerID, picks []Number) {
+	for _, pick := range picks {
+		index := pick - 1
+		r.buckets[index] = append(r.buckets[index], playerID)
+	}
+
+	if int(playerID) > len(r.ticketSizes) {
+		r.ticketSizes = append(r.ticketSizes, make([]uint8, int(playerID)-len(r.ticketSizes))...)
+	}
+	r.ticketSizes[playerID-1] = uint8(len(picks))
+}
+
+func (r *kenoRegistry) BeReadyForProcessing() {
+	//
+	// Same as the regular registry, garbage from loading is collected first, and the sparse array is allocated
+	// once, being reset after each draw.
+	//
+	runtime.GC()
+	r.playerHits = make([]uint8, len(r.ticketSizes))
+}
+
+func (r *kenoRegistry) ProcessKenoDraw(draw []Number) KenoReport {
+	for _, pick := range draw {
+		for _, playerID := range r.buckets[pick-1] {
+			r.playerHits[playerID-1]++
+		}
+	}
+
+	report := NewKenoReport()
+	for i, size := range r.ticketSizes {
+		if size > 0 {
+			report.IncrementTicketsHaving(int(size), int(r.playerHits[i]))
+		}
+	}
+
+	return report
+}
+
+func (r *kenoRegistry) ResetLastProcessing() {
+	for i := range r.playerHits {
+		r.playerHits[i] = 0
+	}
+}
+
+func (r *kenoRegistry) HasPlayerPick(playerID PlayerID, pick Number) bool {
+	for _, id := range r.buckets[pick-1] {
+		if id == playerID {
+			return true
+		}
+	}
+	return false
+}
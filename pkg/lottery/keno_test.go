@@ -0,0 +1,43 @@
+package lottery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKenoRegistry(t *testing.T) {
+	registry := NewKenoRegistry()
+	registry.RegisterPlayer(1, []Number{7})
+	registry.RegisterPlayer(2, []Number{80})
+	registry.RegisterPlayer(3, []Number{1, 2, 3, 4, 5})
+	registry.RegisterPlayer(5, []Number{1, 2, 3, 41, 42, 43, 44, 45, 46, 47})
+	registry.RegisterPlayer(6, []Number{50, 51, 52, 53, 54, 55, 56, 57, 58, 59})
+	registry.BeReadyForProcessing()
+
+	assert.True(t, registry.HasPlayerPick(5, 47))
+	assert.False(t, registry.HasPlayerPick(4, 1))
+
+	draw := []Number{1, 2, 3, 4, 7, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25}
+	report := registry.ProcessKenoDraw(draw)
+	registry.ResetLastProcessing()
+
+	assert.Equal(t, 1, report.GetTicketsHaving(1, 1))
+	assert.Equal(t, 1, report.GetTicketsHaving(1, 0))
+	assert.Equal(t, 1, report.GetTicketsHaving(5, 4))
+	assert.Equal(t, 1, report.GetTicketsHaving(10, 3))
+	assert.Equal(t, 1, report.GetTicketsHaving(10, 0))
+	assert.Equal(t, 0, report.GetTicketsHaving(11, 0))
+	assert.Equal(t, 0, report.GetTicketsHaving(5, 6))
+	assert.Equal(t, "1 1 | 0 0 0 | 0 0 0 0 | 0 0 0 0 0 | 0 0 0 0 1 0 | 0 0 0 0 0 0 0 | "+
+		"0 0 0 0 0 0 0 0 | 0 0 0 0 0 0 0 0 0 | 0 0 0 0 0 0 0 0 0 0 | 1 0 0 1 0 0 0 0 0 0 0", report.String())
+
+	report = registry.ProcessKenoDraw([]Number{80, 50, 51, 52, 53, 54, 55, 56, 57, 58, 59, 60, 61, 62, 63, 64, 65, 66,
+		67, 68})
+	registry.ResetLastProcessing()
+
+	assert.Equal(t, 1, report.GetTicketsHaving(1, 1))
+	assert.Equal(t, 1, report.GetTicketsHaving(10, 10))
+	assert.Equal(t, 1, report.GetTicketsHaving(10, 0))
+	assert.Equal(t, 1, report.GetTicketsHaving(5, 0))
+}
@@ -0,0 +1,64 @@
+package lottery
+
+// Metadata holds the optional dimensions of the registered players, such as region, sales channel or purchase day,
+// so that reports can be broken down by any of them, along with their optional attributes, such as account ID.
+type Metadata struct {
+	dimensions []*Dimension
+	attributes []*Attribute
+}
+
+// NewMetadata creates a new [Metadata] without any dimensions.
+func NewMetadata() *Metadata {
+	return &Metadata{}
+}
+
+// Dimension returns the dimension with the given name, creating it if necessary.
+func (m *Metadata) Dimension(name string) *Dimension {
+	if dimension, ok := m.Lookup(name); ok {
+		return dimension
+	}
+
+	dimension := NewDimension(name)
+	m.dimensions = append(m.dimensions, dimension)
+	return dimension
+}
+
+// Lookup returns the dimension with the given name, if it exists.
+func (m *Metadata) Lookup(name string) (*Dimension, bool) {
+	for _, dimension := range m.dimensions {
+		if dimension.Name == name {
+			return dimension, true
+		}
+	}
+	return nil, false
+}
+
+// Names returns the names of all dimensions, in the order they were created.
+func (m *Metadata) Names() []string {
+	names := make([]string, len(m.dimensions))
+	for i, dimension := range m.dimensions {
+		names[i] = dimension.Name
+	}
+	return names
+}
+
+// Attribute returns the attribute with the given name, creating it if necessary.
+func (m *Metadata) Attribute(name string) *Attribute {
+	if attribute, ok := m.LookupAttribute(name); ok {
+		return attribute
+	}
+
+	attribute := NewAttribute(name)
+	m.attributes = append(m.attributes, attribute)
+	return attribute
+}
+
+// LookupAttribute returns the attribute with the given name, if it exists.
+func (m *Metadata) LookupAttribute(name string) (*Attribute, bool) {
+	for _, attribute := range m.attributes {
+		if attribute.Name == name {
+			return attribute, true
+		}
+	}
+	return nil, false
+}
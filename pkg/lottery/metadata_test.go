@@ -0,0 +1,39 @@
+package lottery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadataDimensions(t *testing.T) {
+	metadata := NewMetadata()
+
+	_, ok := metadata.Lookup("region")
+	assert.False(t, ok)
+
+	region := metadata.Dimension("region")
+	channel := metadata.Dimension("channel")
+	assert.Same(t, region, metadata.Dimension("region"))
+
+	found, ok := metadata.Lookup("channel")
+	assert.True(t, ok)
+	assert.Same(t, channel, found)
+
+	assert.Equal(t, []string{"region", "channel"}, metadata.Names())
+}
+
+func TestMetadataAttributes(t *testing.T) {
+	metadata := NewMetadata()
+
+	_, ok := metadata.LookupAttribute("account")
+	assert.False(t, ok)
+
+	account := metadata.Attribute("account")
+	assert.Same(t, account, metadata.Attribute("account"))
+
+	found, ok := metadata.LookupAttribute("account")
+	assert.True(t, ok)
+	assert.Same(t, account, found)
+	assert.Empty(t, metadata.Names())
+}
@@ -0,0 +1,101 @@
+package lottery
+
+import "runtime"
+
+// NumDigits is the number of distinct digits, from 0 to 9, of positional games.
+const NumDigits = 10
+
+// PositionalGame holds the rules of a positional game, such as [Joker]: each ticket is a number of a fixed quantity
+// of digits, matched against the drawn number positionally, from the rightmost digit. The matches of a ticket are how
+// many of its trailing digits are the same as the drawn ones, stopping at the first one that is not.
+type PositionalGame struct {
+	Name   string
+	Digits int
+}
+
+// Joker is the Joker add-on game, a number of 6 digits.
+var Joker = PositionalGame{Name: "joker", Digits: 6}
+
+type positionalRegistry struct {
+	//
+	// Same as the regular registry, we use bucket sort, except that there is one bucket per position and digit, from
+	// the rightmost position, holding the players having that digit at that position.
+	//
+	buckets [][NumDigits]bucketType
+
+	game PositionalGame
+
+	// numPlayers is the highest player ID registered so far, which is the number of players, the same as the regular
+	// registry.
+	numPlayers PlayerID
+
+	// playerMatches counts the trailing digits matched by each player, where the player ID minus 1 is the index.
+	playerMatches []int
+}
+
+// NewPositionalRegistry creates a new registry for the given positional game. Picks are the digits of the numbers,
+// both registered and drawn, from the leftmost to the rightmost, eg: 012345 is given as 0, 1, 2, 3, 4, 5. Reports
+// count the winners by their matched trailing digits, from 2 up to all of them. HasPlayerPick determines whether the
+// number of the player has the given digit at any position.
+func NewPositionalRegistry(game PositionalGame) Registry {
+	return &positionalRegistry{game: game, buckets: make([][NumDigits]bucketType, game.Digits)}
+}
+
+func (r *positionalRegistry) RegisterPlayer(playerID PlayerID, picks []Number) {
+	for position := range r.buckets {
+		digit := picks[len(picks)-1-position]
+		r.buckets[position][digit] = append(r.buckets[position][digit], playerID)
+	}
+	r.numPlayers = max(r.numPlayers, playerID)
+}
+
+func (r *positionalRegistry) BeReadyForProcessing() {
+	runtime.GC()
+	r.playerMatches = make([]int, r.numPlayers)
+}
+
+func (r *positionalRegistry) ProcessLotteryPicks(picks []Number) Report {
+	//
+	// Positions are processed from the rightmost one. A player matching the digit at a position only counts it if
+	// all digits to its right were matched as well, i.e., if its count is exactly that position, so that matches
+	// stop at the first digit that is not the same.
+	//
+	for position := range r.buckets {
+		digit := picks[len(picks)-1-position]
+		for _, playerID := range r.buckets[position][digit] {
+			if r.playerMatches[playerID-1] == position {
+				r.playerMatches[playerID-1]++
+			}
+		}
+	}
+
+	//
+	// The same as the regular registry, every player is counted directly.
+	//
+	report := newReport(r.game.Digits)
+	for _, count := range r.playerMatches {
+		report.IncrementWinnersHaving(count)
+	}
+
+	return report
+}
+
+func (r *positionalRegistry) ResetLastProcessing() {
+	for i := range r.playerMatches {
+		r.playerMatches[i] = 0
+	}
+}
+
+func (r *positionalRegistry) HasPlayerPick(playerID PlayerID, pick Number) bool {
+	if int(pick) >= NumDigits {
+		return false
+	}
+	for position := range r.buckets {
+		for _, id := range r.buckets[position][pick] {
+			if id == playerID {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package lottery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPositionalRegistry(t *testing.T) {
+	registry := NewPositionalRegistry(Joker)
+	registry.RegisterPlayer(1, []Number{1, 2, 3, 4, 5, 6})
+	registry.RegisterPlayer(2, []Number{9, 2, 3, 4, 5, 6})
+	registry.RegisterPlayer(3, []Number{1, 2, 3, 0, 5, 6})
+	registry.RegisterPlayer(4, []Number{6, 5, 4, 3, 2, 1})
+	registry.RegisterPlayer(5, []Number{0, 0, 0, 0, 5, 6})
+	registry.BeReadyForProcessing()
+
+	assert.True(t, registry.HasPlayerPick(5, 0))
+	assert.False(t, registry.HasPlayerPick(5, 1))
+
+	report := registry.ProcessLotteryPicks([]Number{1, 2, 3, 4, 5, 6})
+	registry.ResetLastProcessing()
+
+	//
+	// Player 3 matches 1, 2 and 3 as well, but these are not counted, since its fourth digit from the right differs.
+	//
+	assert.Equal(t, 1, report.GetWinnersHaving(6))
+	assert.Equal(t, 1, report.GetWinnersHaving(5))
+	assert.Equal(t, 0, report.GetWinnersHaving(4))
+	assert.Equal(t, 0, report.GetWinnersHaving(3))
+	assert.Equal(t, 2, report.GetWinnersHaving(2))
+	assert.Equal(t, "2 0 0 1 1", report.String())
+	assert.Equal(t, []int{1, 0, 2, 0, 0, 1, 1}, report.Histogram())
+	assert.NoError(t, CheckConsistency(report, 5))
+
+	report = registry.ProcessLotteryPicks([]Number{6, 5, 4, 3, 2, 0})
+	registry.ResetLastProcessing()
+	assert.Equal(t, "0 0 0 0 0", report.String())
+}
+
+func TestPositionalReportIsInconsistentIfTicketIsCountedTwice(t *testing.T) {
+	registry := NewPositionalRegistry(Joker)
+	registry.RegisterPlayer(1, []Number{1, 2, 3, 4, 5, 6})
+	registry.RegisterPlayer(1, []Number{1, 2, 3, 4, 5, 6})
+	registry.RegisterPlayer(2, []Number{6, 5, 4, 3, 2, 1})
+	registry.BeReadyForProcessing()
+
+	report := registry.ProcessLotteryPicks([]Number{1, 2, 3, 4, 5, 6})
+	assert.Equal(t, 2, report.Total())
+	assert.ErrorIs(t, CheckConsistency(report, 3), ErrInconsistentReport)
+}
@@ -0,0 +1,50 @@
+package lottery
+
+type referenceRegistry struct {
+	// players maps each player ID to its picks, as registered.
+	players map[PlayerID][]Number
+}
+
+// NewReferenceRegistry creates a deliberately simple lottery registry, which stores each player's picks and compares
+// them against every draw, by brute force. It is far too slow for production, but obviously correct, so it serves as
+// the reference for testing the other registries, which must produce identical reports.
+func NewReferenceRegistry() Registry {
+	return &referenceRegistry{players: make(map[PlayerID][]Number)}
+}
+
+func (r *referenceRegistry) RegisterPlayer(playerID PlayerID, picks []Number) {
+	r.players[playerID] = append(r.players[playerID], picks...)
+}
+
+func (r *referenceRegistry) BeReadyForProcessing() {
+}
+
+func (r *referenceRegistry) ProcessLotteryPicks(picks []Number) Report {
+	report := NewReport()
+
+	for _, playerPicks := range r.players {
+		matches := 0
+		for _, playerPick := range playerPicks {
+			for _, pick := range picks {
+				if playerPick == pick {
+					matches++
+				}
+			}
+		}
+		report.IncrementWinnersHaving(matches)
+	}
+
+	return report
+}
+
+func (r *referenceRegistry) ResetLastProcessing() {
+}
+
+func (r *referenceRegistry) HasPlayerPick(playerID PlayerID, pick Number) bool {
+	for _, playerPick := range r.players[playerID] {
+		if playerPick == pick {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package lottery
+
+import (
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReferenceRegistry(t *testing.T) {
+	registry := NewReferenceRegistry()
+
+	registry.RegisterPlayer(1, []Number{11, 22, 33, 44, 55})
+	registry.RegisterPlayer(2, []Number{11, 22, 33, 44, 66})
+	registry.RegisterPlayer(3, []Number{11, 22, 33, 77, 66})
+	registry.RegisterPlayer(4, []Number{11, 22, 88, 77, 66})
+	registry.RegisterPlayer(5, []Number{11, 89, 88, 77, 66})
+	registry.BeReadyForProcessing()
+
+	assert.True(t, registry.HasPlayerPick(3, 77))
+	assert.False(t, registry.HasPlayerPick(3, 55))
+
+	report := registry.ProcessLotteryPicks([]Number{11, 22, 33, 44, 55})
+
+	assert.Equal(t, "1 1 1 1", report.String())
+}
+
+// FuzzRegistriesMatchReference generates random registries and draws, from the seed, the number of players, and the
+// highest number picked, which is kept low at times so that many players pick the same combinations. Players are
+// registered out of order. All registries must report exactly as the reference does, including
+// the players without winning matches, which must add up to the number of players.
+func FuzzRegistriesMatchReference(f *testing.F) {
+	f.Add(int64(1), uint16(0), uint8(MaxNumber))
+	f.Add(int64(2), uint16(1), uint8(NumPicks))
+	f.Add(int64(42), uint16(1000), uint8(12))
+	f.Add(int64(7), uint16(5000), uint8(MaxNumber))
+
+	f.Fuzz(func(t *testing.T, seed int64, numPlayers uint16, maxNumber uint8) {
+		random := rand.New(rand.NewSource(seed))
+		highest := NumPicks + int(maxNumber)%(MaxNumber-NumPicks+1)
+
+		disk, err := NewDiskRegistry(t.TempDir(), 1+random.Intn(int(numPlayers)+1))
+		assert.NoError(t, err)
+		defer func() { assert.NoError(t, disk.(io.Closer).Close()) }()
+
+		reference := NewReferenceRegistry()
+		registries := map[string]Registry{
+			"regular":    NewRegistry(),
+			"compressed": NewCompressedRegistry(0),
+			"disk":       disk,
+		}
+
+		for _, i := range random.Perm(int(numPlayers)) {
+			playerID := PlayerID(i + 1)
+			picks := randomPicks(random, highest)
+			reference.RegisterPlayer(playerID, picks)
+			for _, registry := range registries {
+				registry.RegisterPlayer(playerID, picks)
+			}
+		}
+		for _, registry := range registries {
+			registry.BeReadyForProcessing()
+		}
+
+		for draw := 0; draw < 10; draw++ {
+			picks := randomPicks(random, highest)
+			expected := reference.ProcessLotteryPicks(picks)
+			assert.NoError(t, CheckConsistency(expected, int(numPlayers)))
+			for name, registry := range registries {
+				report := registry.ProcessLotteryPicks(picks)
+				assert.Equal(t, expected.Histogram(), report.Histogram(), "%v registry, draw %v", name, picks)
+				registry.ResetLastProcessing()
+			}
+		}
+	})
+}
@@ -1,13 +1,15 @@
 package lottery
 
-import "runtime"
+import (
+	"runtime"
+)
 
 // Registry registers the lottery players and their picks. It also processes the lottery picks.
 type Registry interface {
 
 	// RegisterPlayer registers a player and its numeric picks.
-	// The playerID is a unique, sequential number starting from 1. If these players are loaded from a file, this could
-	// be the line number.
+	// The playerID is a unique, positive number, sequential starting from 1, without gaps, although players may be
+	// registered in any order. If these players are loaded from a file, this is the position of the ticket.
 	// The picks is a slice containing NumPicks numbers.
 	RegisterPlayer(playerID PlayerID, picks []Number)
 
@@ -36,12 +38,17 @@ type registry struct {
 	// We create several buckets, or bins, one for each possible lottery number.
 	// Assuming the possible lottery numbers are a relatively small set, memory footprint is manageable.
 	//
-	buckets [MaxNumber]bucketType
+	buckets []bucketType
 
-	totalPlayers int
+	// game determines the number of buckets, and the number of matches reported.
+	game Game
+
+	// numPlayers is the highest player ID registered so far, which is the number of players since their IDs are
+	// sequential, and determines the size of playerMatches.
+	numPlayers PlayerID
 
 	//
-	// This is a sparse arrays that counts the matches for all players, where the player ID is the index
+	// This is a sparse arrays that counts the matches for all players, where the player ID minus 1 is the index
 	// of the array. This allows for great efficiency gains when querying the result of a given lottery pick,
 	// since the counts for each player can be accessed by direct array access.
 	//
@@ -57,22 +64,26 @@ type registry struct {
 // This allows for player picks to be efficiently put into buckets, without the wasteful overhead of array resizing
 // during slice appends when the capacity of the array is not known.
 func NewRegistryFromNumberAllocation(allocation []int) Registry {
-	instance := registry{}
-
-	for i := 0; i < MaxNumber; i++ {
-		instance.buckets[i] = make(bucketType, 0, allocation[i])
-	}
-
-	return &instance
+	return NewGameRegistry(Otoslotto, allocation)
 }
 
 // NewRegistry is only used for testing purposes. For production, because of efficiency concerns,
 // [NewRegistryFromNumberAllocation] should be used instead.
 func NewRegistry() Registry {
-	instance := registry{}
+	return NewGameRegistry(Otoslotto, nil)
+}
+
+// NewGameRegistry creates a new lottery registry for the given game, the same way as
+// [NewRegistryFromNumberAllocation] does. The allocation of each number may be nil, if unknown.
+func NewGameRegistry(game Game, allocation []int) Registry {
+	instance := registry{game: game, buckets: make([]bucketType, game.MaxNumber)}
 
-	for i := 0; i < MaxNumber; i++ {
-		instance.buckets[i] = make(bucketType, 0)
+	for i := range instance.buckets {
+		capacity := 0
+		if allocation != nil {
+			capacity = allocation[i]
+		}
+		instance.buckets[i] = make(bucketType, 0, capacity)
 	}
 
 	return &instance
@@ -83,7 +94,7 @@ func (r *registry) RegisterPlayer(playerID PlayerID, picks []Number) {
 		index := pick - 1
 		r.buckets[index] = append(r.buckets[index], playerID)
 	}
-	r.totalPlayers++
+	r.numPlayers = max(r.numPlayers, playerID)
 }
 
 func (r *registry) BeReadyForProcessing() {
@@ -101,7 +112,7 @@ func (r *registry) BeReadyForProcessing() {
 	// It is faster to reset its elements to zero at the end of processing than to
 	// allocate a new array every time.
 	//
-	r.playerMatches = make([]int, r.totalPlayers)
+	r.playerMatches = make([]int, r.numPlayers)
 }
 
 func (r *registry) ProcessLotteryPicks(picks []Number) Report {
@@ -119,7 +130,11 @@ func (r *registry) ProcessLotteryPicks(picks []Number) Report {
 		}
 	}
 
-	report := NewReport()
+	//
+	// Every player is counted directly, with or without matches, so that the report adds up to the number of tickets
+	// only if each one of them was counted exactly once.
+	//
+	report := newReport(r.game.NumPicks)
 	for _, count := range r.playerMatches {
 		report.IncrementWinnersHaving(count)
 	}
@@ -142,3 +157,11 @@ func (r *registry) HasPlayerPick(playerID PlayerID, pick Number) bool {
 	}
 	return false
 }
+
+func (r *registry) VisitMatches(visit func(playerID PlayerID, matches int)) {
+	for i, count := range r.playerMatches {
+		if count > 0 {
+			visit(PlayerID(i+1), count)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package lottery
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -890,3 +891,48 @@ func TestSomePlayerPicksMatchLotteryPicksWithKnownNumberAllocation(t *testing.T)
 	assert.Equal(t, 1, report.GetWinnersHaving(3))
 	assert.Equal(t, 6, report.GetWinnersHaving(2))
 }
+
+func TestSomePlayerPicksMatchLotteryPicksWithPlayersRegisteredOutOfOrder(t *testing.T) {
+	registry := NewRegistry()
+
+	registry.RegisterPlayer(4, []Number{44, 22, 17, 11, 55})
+	registry.RegisterPlayer(1, []Number{19, 11, 30, 16, 15})
+	registry.RegisterPlayer(5, []Number{55, 80, 33, 22, 11})
+	registry.RegisterPlayer(3, []Number{44, 33, 22, 11, 5})
+	registry.RegisterPlayer(2, []Number{10, 22, 55, 88, 6})
+	registry.BeReadyForProcessing()
+
+	report := registry.ProcessLotteryPicks([]Number{55, 11, 33, 22, 44})
+
+	assert.Equal(t, 0, report.GetWinnersHaving(5))
+	assert.Equal(t, 3, report.GetWinnersHaving(4))
+	assert.Equal(t, 0, report.GetWinnersHaving(3))
+	assert.Equal(t, 1, report.GetWinnersHaving(2))
+	assert.Equal(t, []int{0, 1, 1, 0, 3, 0}, report.Histogram())
+	assert.NoError(t, CheckConsistency(report, 5))
+
+	assert.True(t, registry.HasPlayerPick(5, 80))
+	assert.False(t, registry.HasPlayerPick(5, 44))
+}
+
+func TestReportIsInconsistentIfTicketIsCountedTwice(t *testing.T) {
+	registry := NewRegistry()
+
+	registry.RegisterPlayer(1, []Number{44, 22, 17, 11, 55})
+	registry.RegisterPlayer(2, []Number{19, 11, 30, 16, 15})
+	registry.RegisterPlayer(2, []Number{19, 11, 30, 16, 15})
+	registry.BeReadyForProcessing()
+
+	//
+	// Player 2 was registered twice, so its 2 matches are counted twice, as a single player having 4 of them.
+	//
+	report := registry.ProcessLotteryPicks([]Number{55, 11, 33, 22, 19})
+	assert.Equal(t, []int{0, 0, 0, 1, 1, 0}, report.Histogram())
+	assert.ErrorIs(t, CheckConsistency(report, 3), ErrInconsistentReport)
+	registry.ResetLastProcessing()
+
+	report, err := ProcessLotteryPicksContext(context.Background(), registry, []Number{1, 2, 3, 4, 5})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 0, 0, 0, 0, 0}, report.Histogram())
+	assert.ErrorIs(t, CheckConsistency(report, 3), ErrInconsistentReport)
+}
@@ -1,56 +1,114 @@
 package lottery
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 )
 
+// ErrInvalidReport is returned when parsing a report which is not formatted as [Report.String] does.
+var ErrInvalidReport = errors.New("invalid report")
+
+// ErrInconsistentReport is returned when the matches of a report do not add up to the number of tickets processed.
+var ErrInconsistentReport = errors.New("inconsistent report")
+
 // Report tracks and reports the lottery wins. It is built by [lottery.Registry] during processing of lottery picks.
+// Besides the winners, it counts the tickets having fewer than 2 matches, so that it holds the full histogram of
+// matches, and the total number of tickets processed.
 type Report interface {
 
-	// IncrementWinnersHaving increments the number of winners having the specified amount of matches. For example,
-	// if the given amount of matches is 4, that increases the number of wins for that group. However, if there's less
-	// than 2 matches, that's not considered a win.
+	// IncrementWinnersHaving increments the number of tickets having the specified amount of matches. For example,
+	// if the given amount of matches is 4, that increases the number of wins for that group. If there's less than 2
+	// matches, that's not considered a win, but the ticket is still counted by the histogram.
 	IncrementWinnersHaving(matches int)
 
-	// GetWinnersHaving returns the number of winners having the specified amount of matches.
+	// AddWinnersHaving adds the given count of tickets having the specified amount of matches, as if
+	// [Report.IncrementWinnersHaving] was invoked count times.
+	AddWinnersHaving(matches int, count int)
+
+	// GetWinnersHaving returns the number of winners having the specified amount of matches, or zero if there's less
+	// than 2 matches, since those are not winners.
 	GetWinnersHaving(matches int) int
 
-	// String formats the report for textual representation.
+	// GetTicketsHaving returns the number of tickets having the specified amount of matches, winners or not.
+	GetTicketsHaving(matches int) int
+
+	// Histogram returns the number of tickets having each amount of matches, from 0 up to all picks of the game.
+	Histogram() []int
+
+	// Total returns the total number of tickets counted by the report, winners or not.
+	Total() int
+
+	// String formats the report for textual representation. Only the winners are formatted, from those having 2
+	// matches up to those having all of them.
 	String() string
 }
 
 type reportType struct {
-	winners [NumPicks - 1]int
+	// matches counts the tickets having from 0 up to all matches of the game, i.e., the full histogram.
+	matches []int
 }
 
+// NewReport creates a new, empty [Report] for the [Otoslotto] game.
 func NewReport() Report {
-	return &reportType{}
+	return NewGameReport(Otoslotto)
+}
+
+// NewGameReport creates a new, empty [Report] for the given game, having a count for each number of matches from 0
+// up to all picks of the game.
+func NewGameReport(game Game) Report {
+	return newReport(game.NumPicks)
+}
+
+func newReport(numPicks int) *reportType {
+	return &reportType{matches: make([]int, numPicks+1)}
 }
 
 func (r *reportType) IncrementWinnersHaving(matches int) {
-	index := matches - 2
-	if index >= 0 {
-		r.winners[index]++
+	if matches >= 0 && matches < len(r.matches) {
+		r.matches[matches]++
+	}
+}
+
+func (r *reportType) AddWinnersHaving(matches int, count int) {
+	if matches >= 0 && matches < len(r.matches) {
+		r.matches[matches] += count
 	}
 }
 
 func (r *reportType) GetWinnersHaving(matches int) int {
-	index := matches - 2
-	if index >= 0 {
-		return r.winners[index]
+	if matches < 2 {
+		return 0
+	}
+	return r.GetTicketsHaving(matches)
+}
+
+func (r *reportType) GetTicketsHaving(matches int) int {
+	if matches >= 0 && matches < len(r.matches) {
+		return r.matches[matches]
 	}
 	return 0
 }
 
+func (r *reportType) Histogram() []int {
+	return append([]int{}, r.matches...)
+}
+
+func (r *reportType) Total() int {
+	total := 0
+	for _, count := range r.matches {
+		total += count
+	}
+	return total
+}
+
 func (r *reportType) String() string {
 	var output strings.Builder
-	length := len(r.winners)
+	winners := r.matches[2:]
 
-	for i := 0; i < length; i++ {
-		count := r.winners[i]
-
-		if i != length-1 {
+	for i, count := range winners {
+		if i != len(winners)-1 {
 			output.WriteString(fmt.Sprintf("%v ", count))
 		} else {
 			output.WriteString(fmt.Sprintf("%v", count))
@@ -59,3 +117,42 @@ func (r *reportType) String() string {
 
 	return output.String()
 }
+
+// CheckConsistency checks that the histogram of the report adds up to the given number of tickets, i.e., that every
+// ticket was counted exactly once, and that no count is negative. Otherwise, returns an error wrapping
+// [ErrInconsistentReport]. Registries count every ticket directly, with or without matches, so a ticket counted
+// twice, or not at all, is caught.
+func CheckConsistency(report Report, tickets int) error {
+	for matches, count := range report.Histogram() {
+		if count < 0 {
+			return fmt.Errorf("%w: %v tickets having %v matches", ErrInconsistentReport, count, matches)
+		}
+	}
+	if total := report.Total(); total != tickets {
+		return fmt.Errorf("%w: matches add up to %v tickets, but there are %v", ErrInconsistentReport, total, tickets)
+	}
+	return nil
+}
+
+// ParseReport parses a report formatted as [Report.String] does, eg: a published report, from the number of winners
+// having the fewest matches to the number of winners having all of them, separated by whitespace. Since tickets
+// having fewer than 2 matches are not formatted, they are not counted by the parsed report.
+func ParseReport(line string) (Report, error) {
+	report := newReport(NumPicks)
+	winners := report.matches[2:]
+
+	fields := strings.Fields(line)
+	if len(fields) != len(winners) {
+		return nil, fmt.Errorf("%w: expected %v counts, got %v", ErrInvalidReport, len(winners), len(fields))
+	}
+
+	for i, field := range fields {
+		count, err := strconv.Atoi(field)
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("%w: count '%v' is not a non-negative number", ErrInvalidReport, field)
+		}
+		winners[i] = count
+	}
+
+	return report, nil
+}
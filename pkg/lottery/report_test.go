@@ -42,7 +42,7 @@ func TestReportIncremented(t *testing.T) {
 	report.IncrementWinnersHaving(3)
 	report.IncrementWinnersHaving(3)
 
-	// NO EFFECT
+	// NOT WINNERS, ONLY COUNTED BY THE HISTOGRAM
 	report.IncrementWinnersHaving(1)
 	report.IncrementWinnersHaving(1)
 	report.IncrementWinnersHaving(1)
@@ -54,6 +54,77 @@ func TestReportIncremented(t *testing.T) {
 	assert.Equal(t, 5, report.GetWinnersHaving(3))
 	assert.Equal(t, 4, report.GetWinnersHaving(4))
 	assert.Equal(t, 3, report.GetWinnersHaving(5))
+	assert.Equal(t, 0, report.GetWinnersHaving(1))
+	assert.Equal(t, 6, report.GetTicketsHaving(1))
 
 	assert.Equal(t, report.String(), "6 5 4 3")
 }
+
+func TestReportAdded(t *testing.T) {
+	report := NewReport()
+
+	report.AddWinnersHaving(2, 600)
+	report.AddWinnersHaving(3, 50)
+	report.AddWinnersHaving(3, 20)
+	report.AddWinnersHaving(5, 1)
+	report.IncrementWinnersHaving(5)
+
+	// NOT WINNERS, ONLY COUNTED BY THE HISTOGRAM
+	report.AddWinnersHaving(1, 1000)
+	report.AddWinnersHaving(0, 1000)
+
+	// NO EFFECT
+	report.AddWinnersHaving(6, 1000)
+	report.AddWinnersHaving(-1, 1000)
+
+	assert.Equal(t, report.String(), "600 70 0 2")
+}
+
+func TestReportHistogram(t *testing.T) {
+	report := NewReport()
+
+	report.AddWinnersHaving(0, 7000)
+	report.AddWinnersHaving(1, 2500)
+	report.AddWinnersHaving(2, 400)
+	report.AddWinnersHaving(3, 90)
+	report.AddWinnersHaving(4, 9)
+	report.IncrementWinnersHaving(5)
+
+	assert.Equal(t, []int{7000, 2500, 400, 90, 9, 1}, report.Histogram())
+	assert.Equal(t, 10000, report.Total())
+	assert.Equal(t, "400 90 9 1", report.String())
+
+	assert.NoError(t, CheckConsistency(report, 10000))
+	err := CheckConsistency(report, 10001)
+	assert.ErrorIs(t, err, ErrInconsistentReport)
+	assert.Equal(t, "inconsistent report: matches add up to 10000 tickets, but there are 10001", err.Error())
+
+	//
+	// The histogram is a copy, so it cannot alter the report.
+	//
+	report.Histogram()[0] = 0
+	assert.Equal(t, 10000, report.Total())
+}
+
+func TestReportIsInconsistentIfAnyCountIsNegative(t *testing.T) {
+	report := NewReport()
+	report.AddWinnersHaving(0, -1)
+	report.AddWinnersHaving(2, 2)
+
+	err := CheckConsistency(report, 1)
+	assert.ErrorIs(t, err, ErrInconsistentReport)
+	assert.Equal(t, "inconsistent report: -1 tickets having 0 matches", err.Error())
+}
+
+func TestParseReport(t *testing.T) {
+	report, err := ParseReport(" 20 1\t0 1 ")
+	assert.NoError(t, err)
+	assert.Equal(t, "20 1 0 1", report.String())
+	assert.Equal(t, 20, report.GetWinnersHaving(2))
+	assert.Equal(t, 1, report.GetWinnersHaving(5))
+
+	for _, line := range []string{"", "20 1 0", "20 1 0 1 0", "20 1 x 1", "20 -1 0 1"} {
+		_, err = ParseReport(line)
+		assert.ErrorIs(t, err, ErrInvalidReport, line)
+	}
+}
@@ -0,0 +1,44 @@
+package lottery
+
+// WinnerVisitor is implemented by registries that keep track of the matches of each player, and can tell which of
+// the drawn numbers each winner matched, eg: for exporting the winners to the payout system.
+type WinnerVisitor interface {
+
+	// VisitWinners invokes visit for every player having at least 2 matches on the last processing of the given
+	// lottery picks, in ascending order of player ID, along with the picks it matched, in the order they were drawn.
+	// The matched slice is reused between invocations, so it must be copied if retained.
+	// Must be invoked after [Registry.ProcessLotteryPicks] with the same picks, and before
+	// [Registry.ResetLastProcessing].
+	VisitWinners(picks []Number, visit func(playerID PlayerID, matched []Number))
+}
+
+func (r *registry) VisitWinners(picks []Number, visit func(playerID PlayerID, matched []Number)) {
+	//
+	// The sparse array only counts the matches of each player, so the buckets of the drawn numbers are traversed
+	// again, flagging which of them each winner matched as a bit, i.e., one byte per player, since no game draws more
+	// than 8 numbers. This takes about as long as processing, and is only done on demand.
+	//
+	flags := make([]uint8, len(r.playerMatches))
+	for i, pick := range picks {
+		for _, playerID := range r.buckets[pick-1] {
+			if r.playerMatches[playerID-1] >= 2 {
+				flags[playerID-1] |= 1 << i
+			}
+		}
+	}
+
+	matched := make([]Number, 0, NumPicks)
+	for index, count := range r.playerMatches {
+		if count < 2 {
+			continue
+		}
+
+		matched = matched[:0]
+		for i, pick := range picks {
+			if flags[index]&(1<<i) != 0 {
+				matched = append(matched, pick)
+			}
+		}
+		visit(PlayerID(index+1), matched)
+	}
+}
@@ -0,0 +1,31 @@
+package lottery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVisitWinners(t *testing.T) {
+	registry := NewRegistry()
+	registry.RegisterPlayer(1, []Number{11, 22, 33, 44, 55})
+	registry.RegisterPlayer(2, []Number{11, 22, 33, 44, 66})
+	registry.RegisterPlayer(4, []Number{11, 66, 77, 88, 89})
+	registry.RegisterPlayer(5, []Number{88, 55, 77, 22, 89})
+	registry.BeReadyForProcessing()
+
+	picks := []Number{55, 11, 33, 22, 44}
+	registry.ProcessLotteryPicks(picks)
+
+	winners := map[PlayerID][]Number{}
+	var order []PlayerID
+	registry.(WinnerVisitor).VisitWinners(picks, func(playerID PlayerID, matched []Number) {
+		winners[playerID] = append([]Number(nil), matched...)
+		order = append(order, playerID)
+	})
+
+	assert.Equal(t, []PlayerID{1, 2, 5}, order)
+	assert.Equal(t, []Number{55, 11, 33, 22, 44}, winners[1])
+	assert.Equal(t, []Number{11, 33, 22, 44}, winners[2])
+	assert.Equal(t, []Number{55, 22}, winners[5])
+}
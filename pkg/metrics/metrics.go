@@ -0,0 +1,140 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+)
+
+// latencyBounds are the upper bounds of the latency histograms, in seconds, around the 100ms goal per report.
+var latencyBounds = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1}
+
+// Metrics holds all metrics of the lottery, exposed in the Prometheus text format. Every metric is labeled by the
+// name of its game, so that several games can share them.
+type Metrics struct {
+	TicketsLoaded  GaugeVec
+	RejectedLines  CounterVec
+	DrawsProcessed CounterVec
+	ProcessLatency HistogramVec
+	ResetLatency   HistogramVec
+	RegistryMemory GaugeVec
+	BucketSize     GaugeVec
+}
+
+// New creates all metrics, which are only exposed once they have a value.
+func New() *Metrics {
+	return &Metrics{
+		TicketsLoaded: NewGaugeVec("lottery_tickets_loaded",
+			"Number of tickets loaded into the registry.", "game"),
+		RejectedLines: NewCounterVec("lottery_rejected_lines_total",
+			"Number of invalid lines rejected while loading tickets, by reason.", "game", "reason"),
+		DrawsProcessed: NewCounterVec("lottery_draws_processed_total",
+			"Number of draws processed.", "game"),
+		ProcessLatency: NewHistogramVec("lottery_draw_processing_seconds",
+			"Time taken to process the lottery picks of a draw.", latencyBounds, "game"),
+		ResetLatency: NewHistogramVec("lottery_draw_reset_seconds",
+			"Time taken to reset the registry after processing a draw.", latencyBounds, "game"),
+		RegistryMemory: NewGaugeVec("lottery_registry_memory_bytes",
+			"Estimated memory taken by the registry.", "game"),
+		BucketSize: NewGaugeVec("lottery_registry_bucket_size",
+			"Number of entries in the bucket of each number.", "game", "number"),
+	}
+}
+
+func (m *Metrics) families() []*family {
+	return []*family{
+		m.TicketsLoaded.family,
+		m.RejectedLines.family,
+		m.DrawsProcessed.family,
+		m.ProcessLatency.family,
+		m.ResetLatency.family,
+		m.RegistryMemory.family,
+		m.BucketSize.family,
+	}
+}
+
+// WriteTo writes all metrics to the output, in the Prometheus text format.
+func (m *Metrics) WriteTo(output io.Writer) (int64, error) {
+	var buffer bytes.Buffer
+	for _, f := range m.families() {
+		f.write(&buffer)
+	}
+	return buffer.WriteTo(output)
+}
+
+// ServeHTTP exposes all metrics, so that Prometheus can scrape them, usually from the "/metrics" path.
+func (m *Metrics) ServeHTTP(writer http.ResponseWriter, _ *http.Request) {
+	writer.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = m.WriteTo(writer)
+}
+
+// ObserveRegistry updates the memory footprint and bucket sizes of the registry of the given game, if it is a
+// [lottery.Inspector].
+func (m *Metrics) ObserveRegistry(game string, registry lottery.Registry) {
+	inspector, ok := registry.(lottery.Inspector)
+	if !ok {
+		return
+	}
+
+	m.RegistryMemory.With(game).Set(float64(inspector.MemoryFootprint()))
+	for i, size := range inspector.BucketSizes() {
+		m.BucketSize.With(game, strconv.Itoa(i+1)).Set(float64(size))
+	}
+}
+
+// Instrument wraps the registry of the given game, so that every draw is counted and timed. The registry is observed
+// once it is ready for processing. Optional interfaces of the registry, such as [lottery.MatchVisitor], are not
+// exposed by the wrapper, so they must be asserted on the registry itself.
+func (m *Metrics) Instrument(game string, registry lottery.Registry) lottery.Registry {
+	return &instrumentedRegistry{Registry: registry, metrics: m, game: game}
+}
+
+type instrumentedRegistry struct {
+	lottery.Registry
+	metrics *Metrics
+	game    string
+}
+
+func (r *instrumentedRegistry) BeReadyForProcessing() {
+	r.Registry.BeReadyForProcessing()
+	r.metrics.ObserveRegistry(r.game, r.Registry)
+}
+
+func (r *instrumentedRegistry) ProcessLotteryPicks(picks []lottery.Number) lottery.Report {
+	start := time.Now()
+	report := r.Registry.ProcessLotteryPicks(picks)
+	r.metrics.ProcessLatency.With(r.game).Observe(time.Since(start).Seconds())
+	r.metrics.DrawsProcessed.With(r.game).Inc()
+	return report
+}
+
+func (r *instrumentedRegistry) ProcessLotteryPicksContext(
+	ctx context.Context, picks []lottery.Number,
+) (lottery.Report, error) {
+	start := time.Now()
+	report, err := lottery.ProcessLotteryPicksContext(ctx, r.Registry, picks)
+	r.metrics.ProcessLatency.With(r.game).Observe(time.Since(start).Seconds())
+	if err == nil {
+		r.metrics.DrawsProcessed.With(r.game).Inc()
+	}
+	return report, err
+}
+
+// Close closes the instrumented registry, if it holds resources such as files.
+func (r *instrumentedRegistry) Close() error {
+	if closer, ok := r.Registry.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (r *instrumentedRegistry) ResetLastProcessing() {
+	start := time.Now()
+	r.Registry.ResetLastProcessing()
+	r.metrics.ResetLatency.With(r.game).Observe(time.Since(start).Seconds())
+}
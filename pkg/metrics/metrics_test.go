@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+)
+
+func TestInstrumentRegistry(t *testing.T) {
+	metrics := New()
+
+	registry := metrics.Instrument("otoslotto", lottery.NewRegistry())
+	registry.RegisterPlayer(1, []lottery.Number{11, 22, 33, 44, 55})
+	registry.RegisterPlayer(2, []lottery.Number{11, 22, 33, 44, 66})
+	registry.BeReadyForProcessing()
+
+	for i := 0; i < 3; i++ {
+		report := registry.ProcessLotteryPicks([]lottery.Number{11, 22, 33, 44, 55})
+		assert.Equal(t, "0 0 1 1", report.String())
+		registry.ResetLastProcessing()
+	}
+
+	report, err := lottery.ProcessLotteryPicksContext(context.Background(), registry, []lottery.Number{11, 22, 33, 44, 66})
+	assert.NoError(t, err)
+	assert.Equal(t, "0 0 1 1", report.String())
+	registry.ResetLastProcessing()
+
+	assert.Equal(t, uint64(4), metrics.DrawsProcessed.With("otoslotto").Value())
+	assert.Equal(t, uint64(4), metrics.ProcessLatency.With("otoslotto").Count())
+	assert.Equal(t, uint64(4), metrics.ResetLatency.With("otoslotto").Count())
+	assert.Equal(t, float64(2), metrics.BucketSize.With("otoslotto", "11").Value())
+	assert.Equal(t, float64(0), metrics.BucketSize.With("otoslotto", "90").Value())
+	assert.Greater(t, metrics.RegistryMemory.With("otoslotto").Value(), float64(0))
+}
+
+func TestInstrumentRegistriesOfSeveralGames(t *testing.T) {
+	metrics := New()
+
+	otoslotto := metrics.Instrument("otoslotto", lottery.NewRegistry())
+	otoslotto.RegisterPlayer(1, []lottery.Number{11, 22, 33, 44, 55})
+	otoslotto.BeReadyForProcessing()
+
+	hatoslotto := metrics.Instrument("hatoslotto", lottery.NewGameRegistry(lottery.Hatoslotto, nil))
+	hatoslotto.RegisterPlayer(1, []lottery.Number{1, 2, 3, 4, 5, 6})
+	hatoslotto.RegisterPlayer(2, []lottery.Number{1, 7, 8, 9, 10, 11})
+	hatoslotto.BeReadyForProcessing()
+
+	otoslotto.ProcessLotteryPicks([]lottery.Number{11, 22, 33, 44, 55})
+	hatoslotto.ProcessLotteryPicks([]lottery.Number{1, 2, 3, 4, 5, 6})
+	hatoslotto.ProcessLotteryPicks([]lottery.Number{1, 2, 3, 4, 5, 7})
+
+	assert.Equal(t, uint64(1), metrics.DrawsProcessed.With("otoslotto").Value())
+	assert.Equal(t, uint64(2), metrics.DrawsProcessed.With("hatoslotto").Value())
+	assert.Equal(t, float64(1), metrics.BucketSize.With("otoslotto", "11").Value())
+	assert.Equal(t, float64(2), metrics.BucketSize.With("hatoslotto", "1").Value())
+	assert.Equal(t, float64(0), metrics.BucketSize.With("hatoslotto", "45").Value())
+}
+
+func TestServeMetrics(t *testing.T) {
+	metrics := New()
+	metrics.TicketsLoaded.With("otoslotto").Set(995)
+	metrics.RejectedLines.With("otoslotto", "invalid quantity of picked numbers").Add(3)
+	metrics.DrawsProcessed.With("otoslotto").Inc()
+	metrics.ProcessLatency.With("otoslotto").Observe(0.02)
+
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, 200, recorder.Code)
+	assert.True(t, strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/plain; version=0.0.4"))
+
+	body := recorder.Body.String()
+	assert.Contains(t, body, `lottery_tickets_loaded{game="otoslotto"} 995`+"\n")
+	assert.Contains(t, body,
+		`lottery_rejected_lines_total{game="otoslotto",reason="invalid quantity of picked numbers"} 3`+"\n")
+	assert.Contains(t, body, `lottery_draws_processed_total{game="otoslotto"} 1`+"\n")
+	assert.Contains(t, body, `lottery_draw_processing_seconds_bucket{game="otoslotto",le="0.025"} 1`+"\n")
+	assert.Contains(t, body, `lottery_draw_processing_seconds_bucket{game="otoslotto",le="0.01"} 0`+"\n")
+	assert.NotContains(t, body, "lottery_draw_reset_seconds")
+}
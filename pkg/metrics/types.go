@@ -0,0 +1,244 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+//
+// A minimal implementation of the Prometheus text exposition format, which is all we need, without pulling in the
+// whole client library. See [https://prometheus.io/docs/instrumenting/exposition_formats/].
+//
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// metric is a single time series, or a histogram, of a family, identified by its label values.
+type metric interface {
+	write(output io.Writer, name string, labels string)
+}
+
+// family is a named metric, having one child metric per combination of label values.
+type family struct {
+	name       string
+	help       string
+	kind       string
+	labelNames []string
+	newMetric  func() metric
+
+	mutex    sync.Mutex
+	children map[string]metric
+}
+
+func newFamily(name string, help string, kind string, labelNames []string, newMetric func() metric) *family {
+	return &family{
+		name:       name,
+		help:       help,
+		kind:       kind,
+		labelNames: labelNames,
+		newMetric:  newMetric,
+		children:   make(map[string]metric),
+	}
+}
+
+// with returns the child metric of the given label values, creating it if necessary.
+func (f *family) with(labelValues []string) metric {
+	if len(labelValues) != len(f.labelNames) {
+		panic(fmt.Sprintf("metric %v expects labels %v, got %v", f.name, f.labelNames, labelValues))
+	}
+
+	pairs := make([]string, len(labelValues))
+	for i, value := range labelValues {
+		pairs[i] = fmt.Sprintf(`%v="%v"`, f.labelNames[i], labelEscaper.Replace(value))
+	}
+	labels := strings.Join(pairs, ",")
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	child, ok := f.children[labels]
+	if !ok {
+		child = f.newMetric()
+		f.children[labels] = child
+	}
+	return child
+}
+
+// write writes all children of this family, sorted by their labels, so that the output is deterministic.
+func (f *family) write(output io.Writer) {
+	f.mutex.Lock()
+	labels := make([]string, 0, len(f.children))
+	for label := range f.children {
+		labels = append(labels, label)
+	}
+	f.mutex.Unlock()
+
+	if len(labels) == 0 {
+		return
+	}
+	sort.Strings(labels)
+
+	_, _ = fmt.Fprintf(output, "# HELP %v %v\n", f.name, f.help)
+	_, _ = fmt.Fprintf(output, "# TYPE %v %v\n", f.name, f.kind)
+	for _, label := range labels {
+		f.mutex.Lock()
+		child := f.children[label]
+		f.mutex.Unlock()
+		child.write(output, f.name, label)
+	}
+}
+
+func writeSample(output io.Writer, name string, labels string, value float64) {
+	formatted := strconv.FormatFloat(value, 'g', -1, 64)
+	if labels == "" {
+		_, _ = fmt.Fprintf(output, "%v %v\n", name, formatted)
+	} else {
+		_, _ = fmt.Fprintf(output, "%v{%v} %v\n", name, labels, formatted)
+	}
+}
+
+// Counter is a value that only goes up, such as the number of processed draws.
+type Counter struct {
+	value atomic.Uint64
+}
+
+// Add increases the counter by the given amount.
+func (c *Counter) Add(amount uint64) {
+	c.value.Add(amount)
+}
+
+// Inc increases the counter by one.
+func (c *Counter) Inc() {
+	c.value.Add(1)
+}
+
+// Value returns the current value of the counter.
+func (c *Counter) Value() uint64 {
+	return c.value.Load()
+}
+
+func (c *Counter) write(output io.Writer, name string, labels string) {
+	writeSample(output, name, labels, float64(c.Value()))
+}
+
+// Gauge is a value that can go up and down, such as the number of loaded tickets.
+type Gauge struct {
+	bits atomic.Uint64
+}
+
+// Set sets the gauge to the given value.
+func (g *Gauge) Set(value float64) {
+	g.bits.Store(math.Float64bits(value))
+}
+
+// Value returns the current value of the gauge.
+func (g *Gauge) Value() float64 {
+	return math.Float64frombits(g.bits.Load())
+}
+
+func (g *Gauge) write(output io.Writer, name string, labels string) {
+	writeSample(output, name, labels, g.Value())
+}
+
+// Histogram counts observations, such as latencies, in cumulative buckets of increasing upper bounds.
+type Histogram struct {
+	bounds []float64
+
+	mutex  sync.Mutex
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func newHistogram(bounds []float64) *Histogram {
+	return &Histogram{bounds: bounds, counts: make([]uint64, len(bounds))}
+}
+
+// Observe adds a single observation to the histogram.
+func (h *Histogram) Observe(value float64) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for i, bound := range h.bounds {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += value
+}
+
+// Count returns the total number of observations.
+func (h *Histogram) Count() uint64 {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return h.count
+}
+
+func (h *Histogram) write(output io.Writer, name string, labels string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	prefix := labels
+	if prefix != "" {
+		prefix += ","
+	}
+
+	for i, bound := range h.bounds {
+		le := strconv.FormatFloat(bound, 'g', -1, 64)
+		writeSample(output, name+"_bucket", fmt.Sprintf(`%vle="%v"`, prefix, le), float64(h.counts[i]))
+	}
+	writeSample(output, name+"_bucket", prefix+`le="+Inf"`, float64(h.count))
+	writeSample(output, name+"_sum", labels, h.sum)
+	writeSample(output, name+"_count", labels, float64(h.count))
+}
+
+// CounterVec is a family of counters, one per combination of label values.
+type CounterVec struct {
+	*family
+}
+
+// NewCounterVec creates a family of counters with the given labels.
+func NewCounterVec(name string, help string, labelNames ...string) CounterVec {
+	return CounterVec{newFamily(name, help, "counter", labelNames, func() metric { return &Counter{} })}
+}
+
+// With returns the counter of the given label values, in the same order as the label names.
+func (v CounterVec) With(labelValues ...string) *Counter {
+	return v.with(labelValues).(*Counter)
+}
+
+// GaugeVec is a family of gauges, one per combination of label values.
+type GaugeVec struct {
+	*family
+}
+
+// NewGaugeVec creates a family of gauges with the given labels.
+func NewGaugeVec(name string, help string, labelNames ...string) GaugeVec {
+	return GaugeVec{newFamily(name, help, "gauge", labelNames, func() metric { return &Gauge{} })}
+}
+
+// With returns the gauge of the given label values, in the same order as the label names.
+func (v GaugeVec) With(labelValues ...string) *Gauge {
+	return v.with(labelValues).(*Gauge)
+}
+
+// HistogramVec is a family of histograms, all with the same buckets, one per combination of label values.
+type HistogramVec struct {
+	*family
+}
+
+// NewHistogramVec creates a family of histograms with the given bucket upper bounds, in increasing order, and labels.
+func NewHistogramVec(name string, help string, bounds []float64, labelNames ...string) HistogramVec {
+	return HistogramVec{newFamily(name, help, "histogram", labelNames, func() metric { return newHistogram(bounds) })}
+}
+
+// With returns the histogram of the given label values, in the same order as the label names.
+func (v HistogramVec) With(labelValues ...string) *Histogram {
+	return v.with(labelValues).(*Histogram)
+}
@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteCounters(t *testing.T) {
+	counters := NewCounterVec("rejected_total", "Rejected lines.", "reason")
+	counters.With("not a number").Add(3)
+	counters.With(`say "hi"`).Inc()
+	counters.With("not a number").Inc()
+
+	var output bytes.Buffer
+	counters.write(&output)
+
+	assert.Equal(t, `# HELP rejected_total Rejected lines.
+# TYPE rejected_total counter
+rejected_total{reason="not a number"} 4
+rejected_total{reason="say \"hi\""} 1
+`, output.String())
+}
+
+func TestWriteGaugeWithoutLabels(t *testing.T) {
+	gauges := NewGaugeVec("tickets", "Tickets.")
+
+	var output bytes.Buffer
+	gauges.write(&output)
+	assert.Empty(t, output.String())
+
+	gauges.With().Set(10_000_000)
+	gauges.write(&output)
+	assert.Equal(t, "# HELP tickets Tickets.\n# TYPE tickets gauge\ntickets 1e+07\n", output.String())
+}
+
+func TestWriteHistogram(t *testing.T) {
+	histograms := NewHistogramVec("latency_seconds", "Latency.", []float64{0.01, 0.1, 1}, "game")
+	histogram := histograms.With("otos")
+	histogram.Observe(0.005)
+	histogram.Observe(0.05)
+	histogram.Observe(0.07)
+	histogram.Observe(3)
+
+	var output bytes.Buffer
+	histograms.write(&output)
+
+	assert.Equal(t, `# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{game="otos",le="0.01"} 1
+latency_seconds_bucket{game="otos",le="0.1"} 3
+latency_seconds_bucket{game="otos",le="1"} 3
+latency_seconds_bucket{game="otos",le="+Inf"} 4
+latency_seconds_sum{game="otos"} 3.125
+latency_seconds_count{game="otos"} 4
+`, output.String())
+}
+
+func TestFailIfLabelsMismatch(t *testing.T) {
+	counters := NewCounterVec("rejected_total", "Rejected lines.", "reason")
+	assert.Panics(t, func() { counters.With() })
+}
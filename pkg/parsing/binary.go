@@ -0,0 +1,261 @@
+package parsing
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+)
+
+//
+// The binary ticket format is a compact interchange format, which loads an order of magnitude faster than text,
+// since there is nothing to parse. All integers are little-endian. It consists of a fixed-size header:
+//
+//	offset  size  field
+//	0       4     magic bytes, "HLTB"
+//	4       1     format version, currently 1
+//	5       1     game spec: maximum lottery number, eg: 90
+//	6       1     game spec: numbers picked per ticket, eg: 5
+//	7       1     reserved, always 0
+//	8       8     count of tickets
+//	16      4     CRC-32 (IEEE) checksum of all tickets
+//
+// followed by the tickets, one after the other, each one taking a byte per picked number. Player IDs are implicit,
+// assigned sequentially starting from 1, in the same order the tickets appear in the file.
+//
+
+var binaryMagic = [4]byte{'H', 'L', 'T', 'B'}
+
+const binaryVersion = 1
+
+type binaryHeader struct {
+	Magic     [4]byte
+	Version   uint8
+	MaxNumber uint8
+	NumPicks  uint8
+	Reserved  uint8
+	Count     uint64
+	Checksum  uint32
+}
+
+var binaryHeaderSize = int64(binary.Size(binaryHeader{}))
+
+// BinaryWriter writes tickets in the binary ticket format. Since the header holds the count and checksum of all
+// tickets, it is only complete after [BinaryWriter.Finish] is invoked.
+type BinaryWriter struct {
+	output   io.WriteSeeker
+	buffer   *bufio.Writer
+	checksum hash.Hash32
+	count    uint64
+}
+
+// NewBinaryWriter creates a new [BinaryWriter], writing a placeholder header at the current output position.
+func NewBinaryWriter(output io.WriteSeeker) (*BinaryWriter, error) {
+	writer := &BinaryWriter{
+		output:   output,
+		buffer:   bufio.NewWriter(output),
+		checksum: crc32.NewIEEE(),
+	}
+
+	if err := binary.Write(writer.buffer, binary.LittleEndian, writer.header()); err != nil {
+		return nil, err
+	}
+
+	return writer, nil
+}
+
+// Write writes the picks of a single ticket. These are expected to be valid, as returned by [ParseLine].
+func (w *BinaryWriter) Write(picks []lottery.Number) error {
+	if len(picks) != lottery.NumPicks {
+		return ErrInvalidQuantityOfNumbers
+	}
+
+	if _, err := w.buffer.Write(picks); err != nil {
+		return err
+	}
+	_, _ = w.checksum.Write(picks)
+	w.count++
+
+	return nil
+}
+
+// Finish flushes all tickets, then rewinds the output to complete the header with their count and checksum.
+// It does not close the output.
+func (w *BinaryWriter) Finish() error {
+	if err := w.buffer.Flush(); err != nil {
+		return err
+	}
+
+	if _, err := w.output.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err := binary.Write(w.output, binary.LittleEndian, w.header()); err != nil {
+		return err
+	}
+
+	_, err := w.output.Seek(0, io.SeekEnd)
+	return err
+}
+
+func (w *BinaryWriter) header() binaryHeader {
+	return binaryHeader{
+		Magic:     binaryMagic,
+		Version:   binaryVersion,
+		MaxNumber: lottery.MaxNumber,
+		NumPicks:  lottery.NumPicks,
+		Count:     w.count,
+		Checksum:  w.checksum.Sum32(),
+	}
+}
+
+// LoadBinaryFile loads a file in the binary ticket format into a new [lottery.Registry] instance. The header must
+// match the game spec and the checksum of the tickets. Invalid tickets are handled according to the given
+// [LoadOptions], the same way as [LoadFile] does, where the line number is the position of the ticket in the file.
+func LoadBinaryFile(fileName string, options LoadOptions) (lottery.Registry, LoadSummary, error) {
+	if err := checkFormatSupportsGame(BinaryFormat, options.game()); err != nil {
+		return nil, LoadSummary{}, err
+	}
+
+	c, err := parseBinaryFile(fileName)
+	if err != nil {
+		return nil, LoadSummary{}, err
+	}
+
+	return buildRegistry(context.Background(), []*chunk{c}, options, nil)
+}
+
+// parseBinaryFile reads and validates all tickets of a binary file into a single chunk.
+func parseBinaryFile(fileName string) (*chunk, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	return readBinary(bufio.NewReader(file), info.Size())
+}
+
+// readBinary reads and validates all tickets of a binary input of the given size into a single chunk.
+func readBinary(input io.Reader, size int64) (*chunk, error) {
+	var header binaryHeader
+	if err := binary.Read(input, binary.LittleEndian, &header); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBinaryFile, err)
+	}
+
+	if header.Magic != binaryMagic {
+		return nil, fmt.Errorf("%w: unknown magic bytes", ErrInvalidBinaryFile)
+	}
+	if header.Version != binaryVersion {
+		return nil, fmt.Errorf("%w: unsupported version %v", ErrInvalidBinaryFile, header.Version)
+	}
+	if header.MaxNumber != lottery.MaxNumber || header.NumPicks != lottery.NumPicks {
+		return nil, fmt.Errorf("%w: game spec is %v numbers out of %v, expected %v out of %v", ErrInvalidBinaryFile,
+			header.NumPicks, header.MaxNumber, lottery.NumPicks, lottery.MaxNumber)
+	}
+
+	bodySize := int64(header.Count) * lottery.NumPicks
+	if bodySize != size-binaryHeaderSize {
+		return nil, fmt.Errorf("%w: expected %v tickets in %v bytes, found %v bytes", ErrInvalidBinaryFile,
+			header.Count, bodySize, size-binaryHeaderSize)
+	}
+
+	body := make([]lottery.Number, bodySize)
+	if _, err := io.ReadFull(input, body); err != nil {
+		return nil, err
+	}
+
+	if crc32.ChecksumIEEE(body) != header.Checksum {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrInvalidBinaryFile)
+	}
+
+	//
+	// Valid tickets are compacted in place, so that no copy is necessary if all of them are valid.
+	//
+	c := &chunk{}
+	valid := 0
+
+	for i := 0; i < len(body); i += lottery.NumPicks {
+		c.lines++
+		picks := body[i : i+lottery.NumPicks]
+
+		if err := validatePicks(lottery.Otoslotto, picks); err != nil {
+			c.rejections = append(c.rejections, rejection{line: c.lines, content: formatPicks(picks), err: err})
+			continue
+		}
+
+		for _, pick := range picks {
+			c.allocation[pick-1]++
+		}
+		copy(body[valid:], picks)
+		valid += lottery.NumPicks
+	}
+
+	c.picks = body[:valid]
+
+	return c, nil
+}
+
+// ConvertTextToBinary converts a text file, as accepted by [LoadFile], into the binary ticket format. Invalid lines
+// are handled according to the given [LoadOptions], and are left out of the binary file, so the player IDs are
+// preserved.
+func ConvertTextToBinary(textFileName string, binaryFileName string, options LoadOptions) (LoadSummary, error) {
+	chunks, err := parseTextFile(context.Background(), textFileName, lottery.Otoslotto, defaultNumChunks())
+	if err != nil {
+		return LoadSummary{}, err
+	}
+
+	summary, err := reviewRejections(chunks, options)
+	if err != nil {
+		return summary, err
+	}
+
+	return summary, writeBinaryFile(binaryFileName, chunks)
+}
+
+func writeBinaryFile(fileName string, chunks []*chunk) (err error) {
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	writer, err := NewBinaryWriter(file)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range chunks {
+		for i := 0; i < len(c.picks); i += lottery.NumPicks {
+			if err = writer.Write(c.picks[i : i+lottery.NumPicks]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return writer.Finish()
+}
+
+func formatPicks(picks []lottery.Number) string {
+	fields := make([]string, len(picks))
+	for i, pick := range picks {
+		fields[i] = strconv.Itoa(int(pick))
+	}
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,138 @@
+package parsing
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+)
+
+func TestConvertTextToBinaryAndLoad(t *testing.T) {
+	binaryFile := t.TempDir() + "/1k-players.bin"
+
+	summary, err := ConvertTextToBinary("testdata/bogus.txt", binaryFile, LoadOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 995, summary.Players)
+	assert.Equal(t, 5, summary.Rejected())
+
+	info, err := os.Stat(binaryFile)
+	assert.NoError(t, err)
+	assert.Equal(t, binaryHeaderSize+995*lottery.NumPicks, info.Size())
+
+	expected, _, err := LoadFile("testdata/bogus.txt", LoadOptions{})
+	assert.NoError(t, err)
+	expected.BeReadyForProcessing()
+
+	actual, actualSummary, err := LoadBinaryFile(binaryFile, LoadOptions{Policy: Strict})
+	assert.NoError(t, err)
+	assert.Equal(t, 995, actualSummary.Players)
+	assert.Equal(t, 0, actualSummary.Rejected())
+	actual.BeReadyForProcessing()
+
+	assert.True(t, actual.HasPlayerPick(14, 12))
+	assert.True(t, actual.HasPlayerPick(14, 32))
+	assert.False(t, actual.HasPlayerPick(14, 10))
+
+	for _, draw := range [][]lottery.Number{{12, 83, 73, 26, 32}, {11, 7, 24, 48, 29}} {
+		assert.Equal(t, expected.ProcessLotteryPicks(draw).String(), actual.ProcessLotteryPicks(draw).String())
+		expected.ResetLastProcessing()
+		actual.ResetLastProcessing()
+	}
+}
+
+func TestConvertTextToBinaryFailIfStrictAndHasBogusLines(t *testing.T) {
+	binaryFile := t.TempDir() + "/bogus.bin"
+
+	_, err := ConvertTextToBinary("testdata/bogus.txt", binaryFile, LoadOptions{Policy: Strict})
+	assert.ErrorIs(t, err, ErrRejectedLines)
+	assert.NoFileExists(t, binaryFile)
+}
+
+func TestLoadBinaryFileRejectingInvalidTickets(t *testing.T) {
+	binaryFile := writeBinaryTickets(t, [][]lottery.Number{
+		{1, 2, 3, 4, 5},
+		{1, 2, 3, 4, 91},
+		{10, 20, 30, 40, 50},
+		{10, 20, 30, 20, 50},
+	})
+
+	registry, summary, err := LoadBinaryFile(binaryFile, LoadOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, summary.Lines)
+	assert.Equal(t, 2, summary.Players)
+	assert.Equal(t, map[error]int{ErrNumberOutOfRange: 1, ErrNoRepeatedNumbers: 1}, summary.Rejections)
+
+	assert.True(t, registry.HasPlayerPick(1, 5))
+	assert.True(t, registry.HasPlayerPick(2, 50))
+	assert.False(t, registry.HasPlayerPick(2, 5))
+}
+
+func TestLoadBinaryFileFailIfAnotherGame(t *testing.T) {
+	binaryFile := writeBinaryTickets(t, [][]lottery.Number{{1, 2, 3, 4, 5}})
+
+	registry, _, err := LoadBinaryFile(binaryFile, LoadOptions{Game: lottery.Hatoslotto})
+	assert.ErrorIs(t, err, ErrUnsupportedGame)
+	assert.Nil(t, registry)
+}
+
+func TestLoadBinaryFileFailIfCorrupted(t *testing.T) {
+	binaryFile := writeBinaryTickets(t, [][]lottery.Number{{1, 2, 3, 4, 5}, {10, 20, 30, 40, 50}})
+
+	contents, err := os.ReadFile(binaryFile)
+	assert.NoError(t, err)
+	contents[len(contents)-1] = 51
+	assert.NoError(t, os.WriteFile(binaryFile, contents, 0o600))
+
+	_, _, err = LoadBinaryFile(binaryFile, LoadOptions{})
+	assert.ErrorIs(t, err, ErrInvalidBinaryFile)
+	assert.ErrorContains(t, err, "checksum mismatch")
+}
+
+func TestLoadBinaryFileFailIfTruncated(t *testing.T) {
+	binaryFile := writeBinaryTickets(t, [][]lottery.Number{{1, 2, 3, 4, 5}, {10, 20, 30, 40, 50}})
+
+	contents, err := os.ReadFile(binaryFile)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(binaryFile, contents[:len(contents)-2], 0o600))
+
+	_, _, err = LoadBinaryFile(binaryFile, LoadOptions{})
+	assert.ErrorIs(t, err, ErrInvalidBinaryFile)
+}
+
+func TestLoadBinaryFileFailIfNotBinary(t *testing.T) {
+	_, _, err := LoadBinaryFile("testdata/1k-players.txt", LoadOptions{})
+	assert.ErrorIs(t, err, ErrInvalidBinaryFile)
+}
+
+func TestDetectFormat(t *testing.T) {
+	binaryFile := writeBinaryTickets(t, [][]lottery.Number{{1, 2, 3, 4, 5}})
+
+	for fileName, expected := range map[string]Format{
+		binaryFile:                BinaryFormat,
+		"testdata/tickets.csv":    CSVFormat,
+		"testdata/1k-players.txt": TextFormat,
+	} {
+		format, err := DetectFormat(fileName)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, format, fileName)
+	}
+}
+
+func writeBinaryTickets(t *testing.T, tickets [][]lottery.Number) string {
+	fileName := t.TempDir() + "/tickets.bin"
+
+	file, err := os.Create(fileName)
+	assert.NoError(t, err)
+	defer func() { _ = file.Close() }()
+
+	writer, err := NewBinaryWriter(file)
+	assert.NoError(t, err)
+	for _, picks := range tickets {
+		assert.NoError(t, writer.Write(picks))
+	}
+	assert.NoError(t, writer.Finish())
+
+	return fileName
+}
@@ -0,0 +1,160 @@
+package parsing
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+)
+
+// chunk is a byte range of the input file, aligned on line boundaries, so that it can be parsed independently of
+// the other chunks. Inputs that cannot be split, such as CSV files, are parsed as a single chunk.
+type chunk struct {
+	// source is the name of the file this chunk belongs to, when loading from multiple files.
+	source string
+
+	offset int64
+	length int64
+
+	// lines is the number of lines found in this chunk, valid or not.
+	lines int
+
+	// game determines the number of picks of each line of text files. Other formats are always [lottery.Otoslotto].
+	game lottery.Game
+
+	// picks holds the player picks of all valid lines, contiguously. Each player takes as many elements as the
+	// number of picks of the game. Storing the picks as compact bytes avoids having to read the file twice.
+	picks []lottery.Number
+
+	allocation [lottery.MaxNumber]int
+
+	rejections []rejection
+
+	err error
+}
+
+// rejection is a line that could not be parsed.
+type rejection struct {
+	// line is the line number, relative to the beginning of the chunk, starting from 1.
+	line    int
+	content string
+	err     *ParseError
+}
+
+// splitIntoChunks splits the input of the given size into, at most, numChunks byte ranges of similar size. Each range
+// begins right after a newline, so that no line is split between two chunks.
+func splitIntoChunks(input io.ReaderAt, size int64, numChunks int) ([]*chunk, error) {
+	if numChunks < 1 {
+		numChunks = 1
+	}
+
+	var chunks []*chunk
+	var start int64
+
+	for i := 1; i <= numChunks && start < size; i++ {
+		end := size
+		if i < numChunks {
+			var err error
+			if end, err = findLineStart(input, size*int64(i)/int64(numChunks), size); err != nil {
+				return nil, err
+			}
+		}
+
+		if end > start {
+			chunks = append(chunks, &chunk{offset: start, length: end - start})
+			start = end
+		}
+	}
+
+	return chunks, nil
+}
+
+// findLineStart finds the beginning of the first line that starts at the given position or after it.
+// Returns the input size if there are no more lines.
+func findLineStart(input io.ReaderAt, position int64, size int64) (int64, error) {
+	if position <= 0 {
+		return 0, nil
+	}
+
+	buffer := make([]byte, 4096)
+
+	//
+	// If the byte right before the given position is a newline, the position is already the beginning of a line.
+	//
+	for offset := position - 1; offset < size; offset += int64(len(buffer)) {
+		n, err := input.ReadAt(buffer, offset)
+		if i := bytes.IndexByte(buffer[:n], '\n'); i >= 0 {
+			return offset + int64(i) + 1, nil
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return size, nil
+}
+
+// checkInterval is how many lines are parsed between checks for cancellation, so that checking is cheap.
+const checkInterval = 1 << 14
+
+// parseChunks parses all chunks concurrently, one goroutine per chunk, until done or the context is done.
+func parseChunks(ctx context.Context, input io.ReaderAt, chunks []*chunk) error {
+	var wg sync.WaitGroup
+
+	for _, c := range chunks {
+		wg.Add(1)
+		go func(c *chunk) {
+			defer wg.Done()
+			c.parse(ctx, input)
+		}(c)
+	}
+
+	wg.Wait()
+
+	for _, c := range chunks {
+		if c.err != nil {
+			return c.err
+		}
+	}
+
+	return nil
+}
+
+func (c *chunk) parse(ctx context.Context, input io.ReaderAt) {
+	scanner := bufio.NewScanner(io.NewSectionReader(input, c.offset, c.length))
+	picks := make([]lottery.Number, c.game.NumPicks)
+
+	for scanner.Scan() {
+		if c.lines%checkInterval == 0 {
+			if c.err = lottery.ContextError(ctx); c.err != nil {
+				return
+			}
+		}
+		c.lines++
+
+		line := scanner.Text()
+		if err := ParseGameLine(c.game, line, picks); err != nil {
+			var parseError *ParseError
+			if !errors.As(err, &parseError) {
+				c.err = err
+				return
+			}
+			c.rejections = append(c.rejections, rejection{line: c.lines, content: line, err: parseError})
+			continue
+		}
+
+		for _, pick := range picks {
+			c.allocation[pick-1]++
+		}
+		c.picks = append(c.picks, picks...)
+	}
+
+	c.err = scanner.Err()
+}
@@ -0,0 +1,84 @@
+package parsing
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+)
+
+func TestSplitIntoChunksAlignedOnLines(t *testing.T) {
+	input := "1 2 3 4 5\n6 7 8 9 10\n11 12 13 14 15\n16 17 18 19 20\n"
+
+	for numChunks := 1; numChunks <= len(input)+1; numChunks++ {
+		chunks, err := splitIntoChunks(strings.NewReader(input), int64(len(input)), numChunks)
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, len(chunks), numChunks)
+
+		var offset int64
+		for _, c := range chunks {
+			assert.Equal(t, offset, c.offset)
+			assert.Greater(t, c.length, int64(0))
+			if c.offset > 0 {
+				assert.Equal(t, byte('\n'), input[c.offset-1])
+			}
+			offset += c.length
+		}
+		assert.Equal(t, int64(len(input)), offset)
+	}
+}
+
+func TestSplitEmptyInputIntoChunks(t *testing.T) {
+	chunks, err := splitIntoChunks(strings.NewReader(""), 0, 8)
+	assert.NoError(t, err)
+	assert.Empty(t, chunks)
+}
+
+func TestLoadFileInChunksIsSameRegardlessOfChunks(t *testing.T) {
+	fileNames := []string{
+		"testdata/1k-players.txt",
+		"testdata/1k-players_no-newline-at-end.txt",
+		"testdata/bogus.txt",
+	}
+	draws := [][]lottery.Number{
+		{12, 83, 73, 26, 32},
+		{11, 7, 24, 48, 29},
+		{1, 2, 3, 4, 5},
+	}
+
+	for _, fileName := range fileNames {
+		expected, _, err := loadFileInChunks(context.Background(), fileName, LoadOptions{}, 1)
+		assert.NoError(t, err)
+		expected.BeReadyForProcessing()
+
+		for _, numChunks := range []int{2, 3, 7, 64, 5000} {
+			actual, _, err := loadFileInChunks(context.Background(), fileName, LoadOptions{}, numChunks)
+			assert.NoError(t, err)
+			actual.BeReadyForProcessing()
+
+			for _, draw := range draws {
+				assert.Equal(t, expected.ProcessLotteryPicks(draw).String(), actual.ProcessLotteryPicks(draw).String())
+				expected.ResetLastProcessing()
+				actual.ResetLastProcessing()
+			}
+
+			assert.True(t, actual.HasPlayerPick(14, 12))
+			assert.False(t, actual.HasPlayerPick(14, 10))
+		}
+	}
+}
+
+func TestLoadEmptyFile(t *testing.T) {
+	fileName := t.TempDir() + "/empty.txt"
+	assert.NoError(t, os.WriteFile(fileName, nil, 0o600))
+
+	registry, _, err := LoadFile(fileName, LoadOptions{})
+	assert.NoError(t, err)
+	registry.BeReadyForProcessing()
+
+	assert.Equal(t, "0 0 0 0", registry.ProcessLotteryPicks([]lottery.Number{1, 2, 3, 4, 5}).String())
+}
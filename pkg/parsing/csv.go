@@ -0,0 +1,373 @@
+package parsing
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+)
+
+// CSVColumns maps the columns of a CSV file, by their names in the header row, to the ticket fields.
+// Any other columns are ignored.
+type CSVColumns struct {
+	// TicketID is the column holding the explicit ticket ID, which identifies the ticket in the sales system.
+	TicketID string
+
+	// Numbers are the columns holding each of the picked numbers.
+	Numbers [lottery.NumPicks]string
+
+	// Dimensions are the optional columns holding ticket metadata, such as region or sales channel.
+	Dimensions []CSVDimension
+
+	// Account is the optional column holding the account ID of the player, kept under the [AccountAttribute] of the
+	// metadata if [LoadOptions.KeepAccounts] is enabled.
+	Account string
+}
+
+// AccountAttribute is the name of the [lottery.Attribute] holding the account ID of each player.
+const AccountAttribute = "account"
+
+// TicketAttribute is the name of the [lottery.Attribute] holding the explicit ticket ID of each player loaded from a
+// CSV file, kept if [LoadOptions.KeepTicketIDs] is enabled.
+const TicketAttribute = "ticket"
+
+// CSVDimension maps a column of a CSV file to a [lottery.Dimension] of the ticket metadata.
+type CSVDimension struct {
+	// Name is the name of the dimension, for example "region".
+	Name string
+
+	// Column is the column holding the dimension label.
+	Column string
+
+	// Label optionally derives the label from the column value, for example the day from a timestamp. If nil, the
+	// column value is the label. Empty column values are never labeled.
+	Label func(value string) (string, error)
+}
+
+// DayOf derives the day, formatted as YYYY-MM-DD, from a timestamp in the given layout, as understood by
+// [time.Parse]. Useful as [CSVDimension.Label].
+func DayOf(layout string) func(value string) (string, error) {
+	return func(value string) (string, error) {
+		timestamp, err := time.Parse(layout, value)
+		if err != nil {
+			return "", err
+		}
+		return timestamp.Format(time.DateOnly), nil
+	}
+}
+
+// DefaultCSVColumns is the column mapping of the files exported by the sales system, i.e.:
+//
+//	ticket_id,account_id,n1,n2,n3,n4,n5,purchase_time,channel
+//
+// Tickets are labeled by their sales channel and purchase day.
+var DefaultCSVColumns = CSVColumns{
+	TicketID: "ticket_id",
+	Account:  "account_id",
+	Numbers:  [lottery.NumPicks]string{"n1", "n2", "n3", "n4", "n5"},
+	Dimensions: []CSVDimension{
+		{Name: "channel", Column: "channel"},
+		{Name: "purchase_day", Column: "purchase_time", Label: DayOf(time.RFC3339)},
+	},
+}
+
+// LoadCSVFile parses a CSV file with a header row, and fills the player picks into a new [lottery.Registry]
+// instance. Each record holds an explicit ticket ID, given by the column mapping, which must be positive and unique
+// within the file. Ticket IDs are often sparse, so they are not used as player IDs: players are assigned sequential
+// IDs, the same way as [LoadFile] does, and their ticket IDs are kept under the [TicketAttribute] of the metadata if
+// [LoadOptions.KeepTicketIDs] is enabled.
+// Dimension columns, if any, are labeled into the [LoadSummary.Metadata].
+// Invalid records are handled according to the given [LoadOptions], the same way as [LoadFile] does.
+func LoadCSVFile(fileName string, columns CSVColumns, options LoadOptions) (lottery.Registry, LoadSummary, error) {
+	if err := checkFormatSupportsGame(CSVFormat, options.game()); err != nil {
+		return nil, LoadSummary{}, err
+	}
+
+	var metadata *lottery.Metadata
+	if len(columns.Dimensions) > 0 || (options.KeepAccounts && columns.Account != "") || options.KeepTicketIDs {
+		metadata = lottery.NewMetadata()
+		keepAttributes(metadata, options)
+	}
+
+	ctx := context.Background()
+	c, err := parseCSVFile(ctx, fileName, columns, 1, &ticketIDSet{}, metadata)
+	if err != nil {
+		return nil, LoadSummary{}, err
+	}
+
+	return buildRegistry(ctx, []*chunk{c}, options, metadata)
+}
+
+// parseCSVFile parses a CSV file into a single chunk. Ticket IDs already seen are rejected as duplicates.
+func parseCSVFile(
+	ctx context.Context, fileName string, columns CSVColumns, first lottery.PlayerID, seen *ticketIDSet,
+	metadata *lottery.Metadata,
+) (*chunk, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	return parseCSV(ctx, file, columns, first, seen, metadata)
+}
+
+// parseCSV parses all records of a CSV input into a single chunk. Since it is the only chunk, the line numbers of
+// the rejections are absolute, where the header row is line 1. Valid records are assigned sequential player IDs,
+// starting from first, the same IDs they are later registered with, so that they can be labeled into the metadata,
+// if given, along with their account and ticket IDs, if the metadata has the [AccountAttribute] and the
+// [TicketAttribute], respectively. Gives up if the context is done.
+func parseCSV(
+	ctx context.Context, input io.Reader, columns CSVColumns, first lottery.PlayerID, seen *ticketIDSet,
+	metadata *lottery.Metadata,
+) (*chunk, error) {
+	reader := csv.NewReader(input)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read CSV header: %w", err)
+	}
+
+	var dimensions []*lottery.Dimension
+	var accounts, tickets *lottery.Attribute
+	if metadata != nil {
+		for _, dimension := range columns.Dimensions {
+			dimensions = append(dimensions, metadata.Dimension(dimension.Name))
+		}
+		if columns.Account != "" {
+			accounts, _ = metadata.LookupAttribute(AccountAttribute)
+		}
+		tickets, _ = metadata.LookupAttribute(TicketAttribute)
+	}
+
+	indexes, err := resolveCSVColumns(header, columns, accounts != nil)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &chunk{}
+	playerID := first
+	fields := make([]string, lottery.NumPicks)
+	picks := make([]lottery.Number, lottery.NumPicks)
+
+	for {
+		if c.lines%checkInterval == 0 {
+			if err := lottery.ContextError(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		c.lines++
+		lineNumber, _ := reader.FieldPos(0)
+
+		ticketID, err := parseCSVRecord(record, indexes, fields, picks, seen)
+		if err == nil && dimensions != nil {
+			err = labelCSVRecord(record, playerID, indexes, columns.Dimensions, dimensions)
+		}
+		if err != nil {
+			var parseError *ParseError
+			if !errors.As(err, &parseError) {
+				return nil, err
+			}
+			content := strings.Join(record, ",")
+			c.rejections = append(c.rejections, rejection{line: lineNumber, content: content, err: parseError})
+			continue
+		}
+
+		if accounts != nil && indexes.account < len(record) {
+			accounts.Assign(playerID, record[indexes.account])
+		}
+		if tickets != nil {
+			tickets.Assign(playerID, strconv.FormatInt(ticketID, 10))
+		}
+
+		seen.add(ticketID)
+		for _, pick := range picks {
+			c.allocation[pick-1]++
+		}
+		c.picks = append(c.picks, picks...)
+		playerID++
+	}
+
+	return c, nil
+}
+
+// csvIndexes are the positions of the mapped columns within each record.
+type csvIndexes struct {
+	ticketID   int
+	numbers    [lottery.NumPicks]int
+	dimensions []int
+	account    int
+}
+
+// resolveCSVColumns finds the positions of the mapped columns in the header. The account column is only required if
+// withAccount is true.
+func resolveCSVColumns(header []string, columns CSVColumns, withAccount bool) (csvIndexes, error) {
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		positions[name] = i
+	}
+
+	find := func(name string) (int, error) {
+		position, ok := positions[name]
+		if !ok {
+			return 0, fmt.Errorf("%w: '%v'", ErrUnknownColumn, name)
+		}
+		return position, nil
+	}
+
+	var indexes csvIndexes
+	var err error
+
+	if indexes.ticketID, err = find(columns.TicketID); err != nil {
+		return indexes, err
+	}
+	for i, name := range columns.Numbers {
+		if indexes.numbers[i], err = find(name); err != nil {
+			return indexes, err
+		}
+	}
+	indexes.dimensions = make([]int, len(columns.Dimensions))
+	for i, dimension := range columns.Dimensions {
+		if indexes.dimensions[i], err = find(dimension.Column); err != nil {
+			return indexes, err
+		}
+	}
+	if withAccount {
+		if indexes.account, err = find(columns.Account); err != nil {
+			return indexes, err
+		}
+	}
+
+	return indexes, nil
+}
+
+// parseCSVRecord parses the picks and the ticket ID of a record. Ticket IDs may be any positive 64-bit number.
+func parseCSVRecord(
+	record []string, indexes csvIndexes, fields []string, picks []lottery.Number, seen *ticketIDSet,
+) (int64, error) {
+	if indexes.ticketID >= len(record) {
+		return 0, &ParseError{Err: ErrInvalidQuantityOfNumbers}
+	}
+	for i, index := range indexes.numbers {
+		if index >= len(record) {
+			return 0, &ParseError{Err: ErrInvalidQuantityOfNumbers}
+		}
+		fields[i] = record[index]
+	}
+
+	token := record[indexes.ticketID]
+	ticketID, err := strconv.ParseInt(token, 10, 64)
+	if err != nil || ticketID < 1 {
+		return 0, &ParseError{Field: indexes.ticketID + 1, Token: token, Err: ErrInvalidTicketID}
+	}
+
+	if err = parseFields(lottery.Otoslotto, fields, picks); err != nil {
+		var parseError *ParseError
+		if errors.As(err, &parseError) && parseError.Field != 0 {
+			//
+			// The field position is relative to the numbers, so we translate it to the position within the record.
+			//
+			parseError.Field = indexes.numbers[parseError.Field-1] + 1
+		}
+		return 0, err
+	}
+
+	if seen.contains(ticketID) {
+		return 0, &ParseError{Field: indexes.ticketID + 1, Token: token, Err: ErrDuplicateTicketID}
+	}
+
+	return ticketID, nil
+}
+
+// labelCSVRecord assigns the player to the labels of each dimension column. Labels are only derived once the ticket
+// itself is known to be valid, and only assigned once all of them are known to be accepted by their dimensions, so
+// that a rejected ticket is never labeled at all.
+func labelCSVRecord(
+	record []string, playerID lottery.PlayerID, indexes csvIndexes, columns []CSVDimension,
+	dimensions []*lottery.Dimension,
+) error {
+	labels := make([]string, len(columns))
+
+	for i, column := range columns {
+		index := indexes.dimensions[i]
+		if index >= len(record) || record[index] == "" {
+			continue
+		}
+
+		labels[i] = record[index]
+		if column.Label != nil {
+			var err error
+			if labels[i], err = column.Label(record[index]); err != nil {
+				return &ParseError{Field: index + 1, Token: record[index], Err: ErrInvalidMetadata}
+			}
+		}
+
+		if !dimensions[i].Accepts(labels[i]) {
+			return &ParseError{Field: index + 1, Token: record[index], Err: ErrInvalidMetadata}
+		}
+	}
+
+	for i, label := range labels {
+		if label != "" {
+			dimensions[i].Assign(playerID, label)
+		}
+	}
+
+	return nil
+}
+
+// denseTicketIDs is the bound below which ticket IDs are kept in the bitset of a [ticketIDSet], which then takes at
+// most 32 MiB.
+const denseTicketIDs = 1 << 28
+
+// ticketIDSet is a set of ticket IDs. Ticket IDs from the sales system are mostly sequential, so those below
+// [denseTicketIDs] are kept in a bitset, much more compact than a hash map for millions of them. Any larger ones are
+// kept in a hash map, so that a few sparse IDs do not blow up the bitset.
+type ticketIDSet struct {
+	bits   []uint64
+	sparse map[int64]struct{}
+}
+
+func (s *ticketIDSet) contains(ticketID int64) bool {
+	if ticketID >= denseTicketIDs {
+		_, ok := s.sparse[ticketID]
+		return ok
+	}
+
+	index := int(ticketID / 64)
+	return index < len(s.bits) && s.bits[index]&(1<<(uint(ticketID)%64)) != 0
+}
+
+func (s *ticketIDSet) add(ticketID int64) {
+	if ticketID >= denseTicketIDs {
+		if s.sparse == nil {
+			s.sparse = make(map[int64]struct{})
+		}
+		s.sparse[ticketID] = struct{}{}
+		return
+	}
+
+	index := int(ticketID / 64)
+	if index >= len(s.bits) {
+		s.bits = append(s.bits, make([]uint64, index-len(s.bits)+1)...)
+	}
+	s.bits[index] |= 1 << (uint(ticketID) % 64)
+}
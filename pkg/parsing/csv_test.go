@@ -0,0 +1,273 @@
+package parsing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+)
+
+func TestLoadPlayerPicksFromCSVFile(t *testing.T) {
+	registry, summary, err := LoadCSVFile("testdata/tickets.csv", DefaultCSVColumns, LoadOptions{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 9, summary.Lines)
+	assert.Equal(t, 4, summary.Players)
+	assert.Equal(t, map[error]int{
+		ErrNumberOutOfRange:         1,
+		ErrNotANumber:               1,
+		ErrDuplicateTicketID:        1,
+		ErrInvalidTicketID:          1,
+		ErrInvalidQuantityOfNumbers: 1,
+	}, summary.Rejections)
+
+	assert.True(t, registry.HasPlayerPick(1, 12))
+	assert.True(t, registry.HasPlayerPick(1, 32))
+	assert.True(t, registry.HasPlayerPick(2, 11))
+	assert.False(t, registry.HasPlayerPick(2, 1))
+	assert.True(t, registry.HasPlayerPick(4, 44))
+
+	registry.BeReadyForProcessing()
+	report := registry.ProcessLotteryPicks([]lottery.Number{55, 11, 33, 22, 44})
+	assert.Equal(t, "0 0 0 1", report.String())
+}
+
+func TestLoadPlayerPicksFromCSVFileWritingQuarantine(t *testing.T) {
+	quarantineFile := t.TempDir() + "/quarantine.tsv"
+
+	_, _, err := LoadCSVFile("testdata/tickets.csv", DefaultCSVColumns, LoadOptions{QuarantineFile: quarantineFile})
+	assert.NoError(t, err)
+
+	contents, err := os.ReadFile(quarantineFile)
+	assert.NoError(t, err)
+	assert.Equal(t, ""+
+		"6\tfield 7 '91': picked number is out of range\t1004,A-22,55,11,33,22,91,2024-03-04T18:00:00Z,mobile\n"+
+		"7\tfield 3 'x': picked number is not numeric\t1005,A-23,x,11,33,22,44,2024-03-04T18:05:00Z,mobile\n"+
+		"8\tfield 1 '1002': ticket ID is duplicated\t1002,A-24,1,2,3,4,5,2024-03-05T08:00:00Z,retail\n"+
+		"9\tfield 1 '0': ticket ID must be a positive number\t0,A-25,1,2,3,4,5,2024-03-05T08:30:00Z,retail\n"+
+		"10\tinvalid quantity of picked numbers\t1006,A-26,1,2\n",
+		string(contents))
+}
+
+func TestLoadPlayerPicksFromCSVFileFailIfStrictAndHasBogusRecords(t *testing.T) {
+	registry, _, err := LoadCSVFile("testdata/tickets.csv", DefaultCSVColumns, LoadOptions{Policy: Strict})
+	assert.ErrorIs(t, err, ErrRejectedLines)
+	assert.Nil(t, registry)
+}
+
+func TestLoadPlayerPicksFromCSVFileFailIfAnotherGame(t *testing.T) {
+	registry, _, err := LoadCSVFile("testdata/tickets.csv", DefaultCSVColumns, LoadOptions{Game: lottery.Hatoslotto})
+	assert.ErrorIs(t, err, ErrUnsupportedGame)
+	assert.Nil(t, registry)
+}
+
+func TestParseCSVWithCustomColumns(t *testing.T) {
+	input := "" +
+		"e,d,c,b,a,id\n" +
+		"5,4,3,2,1,17\n" +
+		"50,40,30,20,10,3\n"
+
+	columns := CSVColumns{TicketID: "id", Numbers: [lottery.NumPicks]string{"a", "b", "c", "d", "e"}}
+
+	metadata := lottery.NewMetadata()
+	metadata.Attribute(TicketAttribute)
+
+	c, err := parseCSV(context.Background(), strings.NewReader(input), columns, 1, &ticketIDSet{}, metadata)
+	assert.NoError(t, err)
+	assert.Empty(t, c.rejections)
+	assert.Equal(t, []lottery.Number{1, 2, 3, 4, 5, 10, 20, 30, 40, 50}, c.picks)
+
+	tickets, _ := metadata.LookupAttribute(TicketAttribute)
+	assert.Equal(t, "17", tickets.ValueOf(1))
+	assert.Equal(t, "3", tickets.ValueOf(2))
+}
+
+func TestParseCSVFailIfColumnIsMissing(t *testing.T) {
+	input := "ticket_id,n1,n2,n3,n4\n1,2,3,4,5\n"
+
+	_, err := parseCSV(context.Background(), strings.NewReader(input), DefaultCSVColumns, 1, &ticketIDSet{}, nil)
+	assert.ErrorIs(t, err, ErrUnknownColumn)
+}
+
+func TestLoadPlayerPicksFromCSVFileWithMetadata(t *testing.T) {
+	_, summary, err := LoadCSVFile("testdata/tickets.csv", DefaultCSVColumns, LoadOptions{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"channel", "purchase_day"}, summary.Metadata.Names())
+
+	channel, _ := summary.Metadata.Lookup("channel")
+	assert.Equal(t, []string{"web", "retail", "mobile"}, channel.Labels())
+	assert.Equal(t, "web", channel.LabelOf(1))
+	assert.Equal(t, "retail", channel.LabelOf(2))
+	assert.Equal(t, "mobile", channel.LabelOf(4))
+
+	purchaseDay, _ := summary.Metadata.Lookup("purchase_day")
+	assert.Equal(t, "2024-03-02", purchaseDay.LabelOf(2))
+	assert.Equal(t, "2024-03-04", purchaseDay.LabelOf(4))
+
+	//
+	// Rejected tickets must not be labeled.
+	//
+	assert.Equal(t, "", channel.LabelOf(5))
+}
+
+func TestLoadPlayerPicksFromCSVFileKeepingAccounts(t *testing.T) {
+	_, summary, err := LoadCSVFile("testdata/tickets.csv", DefaultCSVColumns, LoadOptions{})
+	assert.NoError(t, err)
+	_, ok := summary.Metadata.LookupAttribute(AccountAttribute)
+	assert.False(t, ok)
+
+	_, summary, err = Load("testdata/tickets.csv", LoadOptions{KeepAccounts: true})
+	assert.NoError(t, err)
+
+	accounts, ok := summary.Metadata.LookupAttribute(AccountAttribute)
+	assert.True(t, ok)
+	assert.Equal(t, "A-17", accounts.ValueOf(1))
+	assert.Equal(t, "A-18", accounts.ValueOf(2))
+	assert.Equal(t, "A-17", accounts.ValueOf(3))
+	assert.Equal(t, "A-21", accounts.ValueOf(4))
+
+	//
+	// Rejected tickets must not be kept, even if their ticket ID was valid.
+	//
+	assert.Equal(t, "", accounts.ValueOf(5))
+}
+
+func TestParseCSVWithCustomDimensions(t *testing.T) {
+	input := "" +
+		"id,a,b,c,d,e,county,sold_at\n" +
+		"1,1,2,3,4,5,Pest,02/03/2024\n" +
+		"2,10,20,30,40,50,,03/03/2024\n" +
+		"3,11,21,31,41,51,Baranya,not a date\n"
+
+	columns := CSVColumns{
+		TicketID: "id",
+		Numbers:  [lottery.NumPicks]string{"a", "b", "c", "d", "e"},
+		Dimensions: []CSVDimension{
+			{Name: "region", Column: "county"},
+			{Name: "day", Column: "sold_at", Label: DayOf("02/01/2006")},
+		},
+	}
+	metadata := lottery.NewMetadata()
+
+	c, err := parseCSV(context.Background(), strings.NewReader(input), columns, 1, &ticketIDSet{}, metadata)
+	assert.NoError(t, err)
+	assert.Len(t, c.picks, 2*lottery.NumPicks)
+
+	assert.Len(t, c.rejections, 1)
+	assert.ErrorIs(t, c.rejections[0].err, ErrInvalidMetadata)
+	assert.Equal(t, 8, c.rejections[0].err.Field)
+
+	region, _ := metadata.Lookup("region")
+	assert.Equal(t, []string{"Pest"}, region.Labels())
+	assert.Equal(t, "Pest", region.LabelOf(1))
+	assert.Equal(t, "", region.LabelOf(2))
+
+	day, _ := metadata.Lookup("day")
+	assert.Equal(t, "2024-03-02", day.LabelOf(1))
+	assert.Equal(t, "2024-03-03", day.LabelOf(2))
+}
+
+func TestParseCSVWithSparseTicketIDs(t *testing.T) {
+	input := "" +
+		"ticket_id,n1,n2,n3,n4,n5\n" +
+		"9223372036854775807,1,2,3,4,5\n" +
+		"2147483648,10,20,30,40,50\n" +
+		"7,11,21,31,41,51\n" +
+		"2147483648,1,2,3,4,5\n" +
+		"9223372036854775808,1,2,3,4,5\n"
+
+	columns := CSVColumns{TicketID: "ticket_id", Numbers: DefaultCSVColumns.Numbers}
+	metadata := lottery.NewMetadata()
+	metadata.Attribute(TicketAttribute)
+	seen := &ticketIDSet{}
+
+	c, err := parseCSV(context.Background(), strings.NewReader(input), columns, 10, seen, metadata)
+	assert.NoError(t, err)
+	assert.Len(t, c.picks, 3*lottery.NumPicks)
+
+	//
+	// Sparse ticket IDs must neither take memory proportional to their value, nor be used as player IDs.
+	//
+	assert.Len(t, seen.bits, 1)
+	assert.Len(t, seen.sparse, 2)
+
+	tickets, _ := metadata.LookupAttribute(TicketAttribute)
+	assert.Equal(t, "9223372036854775807", tickets.ValueOf(10))
+	assert.Equal(t, "2147483648", tickets.ValueOf(11))
+	assert.Equal(t, "7", tickets.ValueOf(12))
+
+	assert.Len(t, c.rejections, 2)
+	assert.ErrorIs(t, c.rejections[0].err, ErrDuplicateTicketID)
+	assert.ErrorIs(t, c.rejections[1].err, ErrInvalidTicketID)
+}
+
+func TestParseCSVNeverLabelsRejectedTicketsPartially(t *testing.T) {
+	input := "" +
+		"id,a,b,c,d,e,channel,county\n" +
+		"1,1,2,3,4,5,web,Pest\n" +
+		"2,10,20,30,40,50,retail,Baranya\n"
+
+	columns := CSVColumns{
+		TicketID: "id",
+		Numbers:  [lottery.NumPicks]string{"a", "b", "c", "d", "e"},
+		Dimensions: []CSVDimension{
+			{Name: "channel", Column: "channel"},
+			{Name: "region", Column: "county"},
+		},
+	}
+	metadata := lottery.NewMetadata()
+
+	//
+	// The region dimension is full, so the second ticket is rejected, and must not be labeled by its channel either.
+	//
+	region := metadata.Dimension("region")
+	for i := 0; i < lottery.MaxLabels-1; i++ {
+		region.Assign(1000, strconv.Itoa(i))
+	}
+
+	c, err := parseCSV(context.Background(), strings.NewReader(input), columns, 1, &ticketIDSet{}, metadata)
+	assert.NoError(t, err)
+	assert.Len(t, c.picks, lottery.NumPicks)
+
+	assert.Len(t, c.rejections, 1)
+	assert.ErrorIs(t, c.rejections[0].err, ErrInvalidMetadata)
+	assert.Equal(t, 8, c.rejections[0].err.Field)
+
+	channel, _ := metadata.Lookup("channel")
+	assert.Equal(t, []string{"web"}, channel.Labels())
+	assert.Equal(t, "web", channel.LabelOf(1))
+	assert.Equal(t, "", channel.LabelOf(2))
+	assert.Equal(t, "Pest", region.LabelOf(1))
+}
+
+func TestLoadPlayerPicksFromCSVFileWithAdditionalDimensions(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "tickets.csv")
+	assert.NoError(t, os.WriteFile(fileName, []byte(""+
+		"ticket_id,account_id,n1,n2,n3,n4,n5,purchase_time,channel,county\n"+
+		"1001,A-17,12,83,73,26,32,2024-03-02T10:15:00Z,web,Pest\n"+
+		"1002,A-18,11,7,24,48,29,2024-03-02T11:20:00Z,retail,Baranya\n"), 0o644))
+
+	options := LoadOptions{Dimensions: []CSVDimension{{Name: "region", Column: "county"}}}
+	_, summary, err := Load(fileName, options)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"channel", "purchase_day", "region"}, summary.Metadata.Names())
+	region, _ := summary.Metadata.Lookup("region")
+	assert.Equal(t, "Pest", region.LabelOf(1))
+	assert.Equal(t, "Baranya", region.LabelOf(2))
+
+	//
+	// The default columns must be left untouched.
+	//
+	assert.Len(t, DefaultCSVColumns.Dimensions, 2)
+
+	_, _, err = Load("testdata/tickets.csv", options)
+	assert.ErrorIs(t, err, ErrUnknownColumn)
+}
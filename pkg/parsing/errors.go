@@ -1,9 +1,90 @@
 package parsing
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 var ErrInvalidQuantityOfNumbers = errors.New("invalid quantity of picked numbers")
 
 var ErrNumberOutOfRange = errors.New("picked number is out of range")
 
 var ErrNoRepeatedNumbers = errors.New("no repeated numbers should be picked")
+
+var ErrNotANumber = errors.New("picked number is not numeric")
+
+var ErrInvalidTicketID = errors.New("ticket ID must be a positive number")
+
+var ErrDuplicateTicketID = errors.New("ticket ID is duplicated")
+
+var ErrInvalidMetadata = errors.New("ticket metadata is invalid")
+
+var ErrUnknownColumn = errors.New("column not found in header")
+
+var ErrInvalidBinaryFile = errors.New("invalid binary ticket file")
+
+var ErrNoSources = errors.New("no ticket files found")
+
+var ErrRejectedLines = errors.New("input has rejected lines")
+
+var ErrUnsupportedGame = errors.New("game is not supported")
+
+var ErrInvalidJokerNumber = errors.New("Joker number must have exactly 6 digits")
+
+// ParseError describes why a line could not be parsed, and where. It wraps one of the sentinel errors, for example
+// [ErrNumberOutOfRange], so it can be inspected with [errors.Is] and [errors.As].
+type ParseError struct {
+	// Source is the name of the file where the error was found, when loading from multiple files.
+	Source string
+
+	// Line is the line number, starting from 1, or 0 if unknown.
+	Line int
+
+	// Field is the position of the offending field within the line, starting from 1, or 0 if the error concerns the
+	// whole line (eg: [ErrInvalidQuantityOfNumbers]).
+	Field int
+
+	// Token is the offending field, as found in the line.
+	Token string
+
+	// Err is the sentinel error describing the kind of failure.
+	Err error
+}
+
+// Reason describes the error without its line number.
+func (e *ParseError) Reason() string {
+	if e.Field == 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("field %v '%v': %v", e.Field, e.Token, e.Err)
+}
+
+// Location describes where the error was found: the line number, prefixed by the source if known.
+// For example, "channel-a.txt:12". Empty if the line number is unknown.
+func (e *ParseError) Location() string {
+	if e.Line == 0 {
+		return ""
+	}
+	if e.Source != "" {
+		return fmt.Sprintf("%v:%v", e.Source, e.Line)
+	}
+	return fmt.Sprintf("%v", e.Line)
+}
+
+func (e *ParseError) Error() string {
+	var output strings.Builder
+	if e.Line != 0 {
+		if e.Source != "" {
+			output.WriteString(fmt.Sprintf("%v: ", e.Location()))
+		} else {
+			output.WriteString(fmt.Sprintf("line %v: ", e.Line))
+		}
+	}
+	output.WriteString(e.Reason())
+	return output.String()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,129 @@
+package parsing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+)
+
+// Format is a supported ticket file format.
+type Format int
+
+const (
+	// TextFormat has one ticket per line, with whitespace-separated numbers. See [LoadFile].
+	TextFormat Format = iota
+
+	// CSVFormat is exported by the sales system, with explicit ticket IDs. See [LoadCSVFile].
+	CSVFormat
+
+	// BinaryFormat is the compact binary ticket format. See [LoadBinaryFile].
+	BinaryFormat
+)
+
+// DetectFormat detects the format of a ticket file. Binary files are detected by their magic bytes, and CSV files
+// by their extension. Any other file is assumed to be text.
+func DetectFormat(fileName string) (Format, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return TextFormat, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var magic [len(binaryMagic)]byte
+	if _, err = io.ReadFull(file, magic[:]); err == nil && magic == binaryMagic {
+		return BinaryFormat, nil
+	} else if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return TextFormat, err
+	}
+
+	if strings.EqualFold(filepath.Ext(fileName), ".csv") {
+		return CSVFormat, nil
+	}
+
+	return TextFormat, nil
+}
+
+// Load loads a ticket file of any supported format, as detected by [DetectFormat]. CSV files are expected to have
+// the [DefaultCSVColumns]. Games other than [lottery.Otoslotto] are only supported by text files.
+func Load(fileName string, options LoadOptions) (lottery.Registry, LoadSummary, error) {
+	return LoadContext(context.Background(), fileName, options)
+}
+
+// LoadContext loads a ticket file as [Load] does, but gives up as soon as the context is done, eg: on shutdown, with
+// [lottery.ErrDeadlineExceeded] if its deadline was hit, or [context.Canceled].
+func LoadContext(ctx context.Context, fileName string, options LoadOptions) (lottery.Registry, LoadSummary, error) {
+	format, err := DetectFormat(fileName)
+	if err != nil {
+		return nil, LoadSummary{}, err
+	}
+
+	var metadata *lottery.Metadata
+	if format == CSVFormat {
+		metadata = lottery.NewMetadata()
+		keepAttributes(metadata, options)
+	}
+
+	chunks, err := parseFile(ctx, fileName, format, options, 1, &ticketIDSet{}, metadata)
+	if err != nil {
+		return nil, LoadSummary{}, err
+	}
+
+	return buildRegistry(ctx, chunks, options, metadata)
+}
+
+// keepAttributes adds the [AccountAttribute] and the [TicketAttribute] to the metadata if the options require keeping
+// account and ticket IDs, respectively, so that they are kept while parsing CSV files.
+func keepAttributes(metadata *lottery.Metadata, options LoadOptions) {
+	if options.KeepAccounts {
+		metadata.Attribute(AccountAttribute)
+	}
+	if options.KeepTicketIDs {
+		metadata.Attribute(TicketAttribute)
+	}
+}
+
+// checkFormatSupportsGame fails unless the format can hold tickets of the given game. Only text files hold tickets of
+// any game; the other formats always hold [lottery.Otoslotto] tickets.
+func checkFormatSupportsGame(format Format, game lottery.Game) error {
+	if format != TextFormat && game != lottery.Otoslotto {
+		return fmt.Errorf("%w: %v tickets can only be loaded from text files", ErrUnsupportedGame, game.Name)
+	}
+	return nil
+}
+
+// parseFile parses a ticket file of the given format into chunks, as tickets of the game of the options. Explicit
+// ticket IDs already seen are rejected as duplicates. CSV files are labeled into the given metadata, by the
+// [DefaultCSVColumns] along with the additional dimensions of the options, where first is the player ID their first
+// valid record is going to be registered with.
+func parseFile(
+	ctx context.Context, fileName string, format Format, options LoadOptions, first lottery.PlayerID,
+	seen *ticketIDSet, metadata *lottery.Metadata,
+) ([]*chunk, error) {
+	var c *chunk
+	var err error
+	game := options.game()
+
+	if err = checkFormatSupportsGame(format, game); err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case BinaryFormat:
+		c, err = parseBinaryFile(fileName)
+	case CSVFormat:
+		c, err = parseCSVFile(ctx, fileName, options.csvColumns(), first, seen, metadata)
+	default:
+		return parseTextFile(ctx, fileName, game, defaultNumChunks())
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return []*chunk{c}, lottery.ContextError(ctx)
+}
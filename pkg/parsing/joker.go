@@ -0,0 +1,90 @@
+package parsing
+
+import (
+	"strings"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+)
+
+// JokerPrefix marks the field of a ticket line holding its Joker number, eg: "45 81 67 78 16 J012345".
+const JokerPrefix = "J"
+
+// ParseJokerNumber parses the digits of a [lottery.Joker] number, from the leftmost to the rightmost one, into digits,
+// which must hold as many elements as the digits of the game. Leading zeros are significant, so the token must have
+// exactly that many digits. If the token is invalid, a [*ParseError] is returned.
+func ParseJokerNumber(token string, digits []lottery.Number) error {
+	if err := parseJokerNumber(token, digits); err != nil {
+		return err
+	}
+	return nil
+}
+
+func parseJokerNumber(token string, digits []lottery.Number) *ParseError {
+	if len(token) != lottery.Joker.Digits || len(digits) != lottery.Joker.Digits {
+		return &ParseError{Token: token, Err: ErrInvalidJokerNumber}
+	}
+
+	for i := 0; i < len(token); i++ {
+		if token[i] < '0' || token[i] > '9' {
+			return &ParseError{Token: token, Err: ErrInvalidJokerNumber}
+		}
+		digits[i] = token[i] - '0'
+	}
+
+	return nil
+}
+
+// ParseLineWithJoker parses a ticket line of the given game, the same way as [ParseGameLine] does, optionally followed
+// by a Joker number, prefixed by [JokerPrefix], as the last field. Returns whether the line had a Joker number, whose
+// digits are parsed into joker, as described by [ParseJokerNumber]. If the line is invalid, a [*ParseError] is
+// returned.
+func ParseLineWithJoker(game lottery.Game, line string, picks []lottery.Number, joker []lottery.Number) (bool, error) {
+	fields := strings.Fields(line)
+
+	last := len(fields) - 1
+	if last < 0 || !strings.HasPrefix(fields[last], JokerPrefix) {
+		return false, parseFields(game, fields, picks)
+	}
+
+	if err := parseFields(game, fields[:last], picks); err != nil {
+		return false, err
+	}
+	if err := parseJokerNumber(strings.TrimPrefix(fields[last], JokerPrefix), joker); err != nil {
+		err.Field = last + 1
+		err.Token = fields[last]
+		return false, err
+	}
+	return true, nil
+}
+
+// LoadJokerFile parses a text file of tickets of the game given by the [LoadOptions], one per line, each one
+// optionally followed by its Joker number, as described by [ParseLineWithJoker]. The picks of the tickets are filled
+// into a new regular [lottery.Registry], and their Joker numbers, if any, into a new [lottery.NewPositionalRegistry],
+// both sharing the same player IDs, which are assigned sequentially to the valid lines. Invalid lines are handled
+// according to the policy and quarantine file of the options, while the kind of registry cannot be chosen.
+func LoadJokerFile(fileName string, options LoadOptions) (lottery.Registry, lottery.Registry, LoadSummary, error) {
+	game := options.game()
+	registry := lottery.NewGameRegistry(game, nil)
+	jokers := lottery.NewPositionalRegistry(lottery.Joker)
+
+	picks := make([]lottery.Number, game.NumPicks)
+	joker := make([]lottery.Number, lottery.Joker.Digits)
+
+	summary, err := loadLines(fileName, options, func(playerID lottery.PlayerID, line string) error {
+		hasJoker, err := ParseLineWithJoker(game, line, picks, joker)
+		if err != nil {
+			return err
+		}
+
+		registry.RegisterPlayer(playerID, picks)
+		if hasJoker {
+			jokers.RegisterPlayer(playerID, joker)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, summary, err
+	}
+
+	return registry, jokers, summary, nil
+}
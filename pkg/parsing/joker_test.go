@@ -0,0 +1,67 @@
+package parsing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+)
+
+func TestParseJokerNumber(t *testing.T) {
+	digits := make([]lottery.Number, lottery.Joker.Digits)
+
+	assert.NoError(t, ParseJokerNumber("012345", digits))
+	assert.Equal(t, []lottery.Number{0, 1, 2, 3, 4, 5}, digits)
+
+	assert.ErrorIs(t, ParseJokerNumber("12345", digits), ErrInvalidJokerNumber)
+	assert.ErrorIs(t, ParseJokerNumber("1234567", digits), ErrInvalidJokerNumber)
+	assert.ErrorIs(t, ParseJokerNumber("12a456", digits), ErrInvalidJokerNumber)
+}
+
+func TestParseLineWithJoker(t *testing.T) {
+	picks := make([]lottery.Number, lottery.NumPicks)
+	joker := make([]lottery.Number, lottery.Joker.Digits)
+
+	hasJoker, err := ParseLineWithJoker(lottery.Otoslotto, "45 81 67 78 16 J907001", picks, joker)
+	assert.NoError(t, err)
+	assert.True(t, hasJoker)
+	assert.Equal(t, []lottery.Number{45, 81, 67, 78, 16}, picks)
+	assert.Equal(t, []lottery.Number{9, 0, 7, 0, 0, 1}, joker)
+
+	hasJoker, err = ParseLineWithJoker(lottery.Otoslotto, "29 66 14 80 41", picks, joker)
+	assert.NoError(t, err)
+	assert.False(t, hasJoker)
+	assert.Equal(t, []lottery.Number{29, 66, 14, 80, 41}, picks)
+
+	_, err = ParseLineWithJoker(lottery.Otoslotto, "45 81 67 78 16 J90700", picks, joker)
+	assert.ErrorIs(t, err, ErrInvalidJokerNumber)
+	assert.Equal(t, "field 6 'J90700': Joker number must have exactly 6 digits", err.Error())
+
+	_, err = ParseLineWithJoker(lottery.Otoslotto, "45 81 67 78 J907001", picks, joker)
+	assert.ErrorIs(t, err, ErrInvalidQuantityOfNumbers)
+
+	_, err = ParseLineWithJoker(lottery.Otoslotto, "", picks, joker)
+	assert.ErrorIs(t, err, ErrInvalidQuantityOfNumbers)
+}
+
+func TestLoadJokerFile(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "tickets.txt")
+	content := "45 81 67 78 16 J123456\n29 66 14 80 41\n58 67 71 32 22 J9\n11 22 33 44 55 J000056\n"
+	assert.NoError(t, os.WriteFile(fileName, []byte(content), 0o644))
+
+	registry, jokers, summary, err := LoadJokerFile(fileName, LoadOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, summary.Lines)
+	assert.Equal(t, 3, summary.Players)
+	assert.Equal(t, 1, summary.Rejections[ErrInvalidJokerNumber])
+	assert.True(t, registry.HasPlayerPick(2, 66))
+	assert.True(t, registry.HasPlayerPick(3, 55))
+
+	jokers.BeReadyForProcessing()
+	report := jokers.ProcessLotteryPicks([]lottery.Number{9, 9, 3, 4, 5, 6})
+	assert.Equal(t, 1, report.GetWinnersHaving(4))
+	assert.Equal(t, 1, report.GetWinnersHaving(2))
+}
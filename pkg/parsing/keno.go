@@ -0,0 +1,105 @@
+package parsing
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+)
+
+// ParseKenoLine parses a textual line representing the picked numbers of a Kenó ticket, the same way as [ParseLine]
+// does, except that any quantity from [lottery.KenoMinPicks] up to [lottery.KenoMaxPicks] may be given, and numbers
+// must be between 1 and [lottery.KenoMaxNumber], inclusive. Returns the picks, as many as given. Kenó draws, which
+// always have the same quantity of numbers, are parsed with [ParseGameLine] and [lottery.Keno] instead.
+func ParseKenoLine(line string) ([]lottery.Number, error) {
+	fields := strings.Fields(line)
+	if len(fields) < lottery.KenoMinPicks || len(fields) > lottery.KenoMaxPicks {
+		return nil, &ParseError{Err: ErrInvalidQuantityOfNumbers}
+	}
+
+	//
+	// The ticket is parsed as if the game picked exactly as many numbers as the ticket does.
+	//
+	ticket := lottery.Game{Name: lottery.Keno.Name, NumPicks: len(fields), MaxNumber: lottery.KenoMaxNumber}
+	picks := make([]lottery.Number, len(fields))
+	if err := parseFields(ticket, fields, picks); err != nil {
+		return nil, err
+	}
+	return picks, nil
+}
+
+// LoadKenoFile parses a text file of Kenó tickets, one per line, as described by [ParseKenoLine], and fills the
+// player picks into a new [lottery.KenoRegistry]. Player IDs are assigned sequentially to the valid lines. Invalid
+// lines are handled according to the policy and quarantine file of the given [LoadOptions], while other options do
+// not apply. Unlike [LoadFile], the file is parsed sequentially, since Kenó has far fewer tickets.
+func LoadKenoFile(fileName string, options LoadOptions) (lottery.KenoRegistry, LoadSummary, error) {
+	registry := lottery.NewKenoRegistry()
+
+	summary, err := loadLines(fileName, options, func(playerID lottery.PlayerID, line string) error {
+		picks, err := ParseKenoLine(line)
+		if err == nil {
+			registry.RegisterPlayer(playerID, picks)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, summary, err
+	}
+
+	return registry, summary, nil
+}
+
+// loadLines parses a text file sequentially, one line at a time, with the given register function, which is given
+// the player ID of the line. Player IDs are assigned sequentially to the valid lines. Lines failing with a
+// [*ParseError] are rejected, and handled according to the policy and quarantine file of the given [LoadOptions].
+func loadLines(
+	fileName string, options LoadOptions, register func(playerID lottery.PlayerID, line string) error,
+) (LoadSummary, error) {
+	summary := LoadSummary{Rejections: make(map[error]int)}
+
+	file, err := os.Open(fileName)
+	if err != nil {
+		return summary, err
+	}
+	defer func() { _ = file.Close() }()
+
+	//
+	// The rejected lines are collected into a single chunk, so that they are handled the same way as the ones of
+	// any other ticket file.
+	//
+	c := &chunk{}
+	var playerID lottery.PlayerID = 1
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		c.lines++
+
+		line := scanner.Text()
+		if err = register(playerID, line); err != nil {
+			var parseError *ParseError
+			if !errors.As(err, &parseError) {
+				return summary, err
+			}
+			c.rejections = append(c.rejections, rejection{line: c.lines, content: line, err: parseError})
+			continue
+		}
+
+		playerID++
+		summary.Players++
+	}
+	if err = scanner.Err(); err != nil {
+		return summary, err
+	}
+
+	if err = handleRejections([]*chunk{c}, options, &summary); err != nil {
+		return summary, err
+	}
+	if options.Policy == Strict && summary.Rejected() > 0 {
+		return summary, fmt.Errorf("%w: %v of %v lines", ErrRejectedLines, summary.Rejected(), summary.Lines)
+	}
+
+	return summary, nil
+}
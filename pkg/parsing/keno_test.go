@@ -0,0 +1,68 @@
+package parsing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+)
+
+func TestParseKenoLine(t *testing.T) {
+	picks, err := ParseKenoLine("80")
+	assert.NoError(t, err)
+	assert.Equal(t, []lottery.Number{80}, picks)
+
+	picks, err = ParseKenoLine("1 2 3 4 5 6 7 8 9 10")
+	assert.NoError(t, err)
+	assert.Equal(t, []lottery.Number{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, picks)
+
+	_, err = ParseKenoLine("")
+	assert.ErrorIs(t, err, ErrInvalidQuantityOfNumbers)
+
+	_, err = ParseKenoLine("1 2 3 4 5 6 7 8 9 10 11")
+	assert.ErrorIs(t, err, ErrInvalidQuantityOfNumbers)
+
+	_, err = ParseKenoLine("1 81")
+	assert.ErrorIs(t, err, ErrNumberOutOfRange)
+	assert.Equal(t, "field 2 '81': picked number is out of range", err.Error())
+
+	_, err = ParseKenoLine("12 5 12")
+	assert.ErrorIs(t, err, ErrNoRepeatedNumbers)
+}
+
+func TestParseKenoDraw(t *testing.T) {
+	draw := make([]lottery.Number, lottery.Keno.NumPicks)
+
+	err := ParseGameLine(lottery.Keno, "1 2 3 4 5 6 7 8 9 10 11 12 13 14 15 16 17 18 19 80", draw)
+	assert.NoError(t, err)
+	assert.Equal(t, lottery.Number(80), draw[19])
+
+	err = ParseGameLine(lottery.Keno, "1 2 3 4 5", draw)
+	assert.ErrorIs(t, err, ErrInvalidQuantityOfNumbers)
+}
+
+func TestLoadKenoFile(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "keno.txt")
+	assert.NoError(t, os.WriteFile(fileName, []byte("7\n1 2 3 4 5\n1 2 81\n\n41 42 43 44 45 46 47 48 49 50\n"), 0o644))
+
+	registry, summary, err := LoadKenoFile(fileName, LoadOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, summary.Lines)
+	assert.Equal(t, 3, summary.Players)
+	assert.Equal(t, 1, summary.Rejections[ErrNumberOutOfRange])
+	assert.Equal(t, 1, summary.Rejections[ErrInvalidQuantityOfNumbers])
+	assert.True(t, registry.HasPlayerPick(3, 50))
+
+	registry.BeReadyForProcessing()
+	report := registry.ProcessKenoDraw([]lottery.Number{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17,
+		18, 19, 20})
+	assert.Equal(t, 1, report.GetTicketsHaving(1, 1))
+	assert.Equal(t, 1, report.GetTicketsHaving(5, 5))
+	assert.Equal(t, 1, report.GetTicketsHaving(10, 0))
+
+	_, _, err = LoadKenoFile(fileName, LoadOptions{Policy: Strict})
+	assert.ErrorIs(t, err, ErrRejectedLines)
+}
@@ -0,0 +1,128 @@
+package parsing
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+)
+
+// LoadPolicy determines how invalid lines are handled while loading a file.
+type LoadPolicy int
+
+const (
+	// Lenient skips invalid lines, printing a warning for each one of them.
+	Lenient LoadPolicy = iota
+
+	// Strict refuses to load a file if any of its lines is invalid. Necessary for regulated draws.
+	Strict
+)
+
+// LoadOptions configures how a file is loaded. The zero value is a lenient policy without quarantine.
+type LoadOptions struct {
+	Policy LoadPolicy
+
+	// Game determines the rules of the tickets, [lottery.Otoslotto] if unset. Other games are only supported by text
+	// files, loaded into a regular registry.
+	Game lottery.Game
+
+	// QuarantineFile is an optional file where every rejected line is written, along with its line number and
+	// reason, separated by tabs. It is written regardless of the policy.
+	QuarantineFile string
+
+	// IndexCombinations counts the tickets per combination into a [lottery.CombinationIndex], in order to detect
+	// duplicate tickets. Disabled by default, since the index may take hundreds of megabytes.
+	IndexCombinations bool
+
+	// Compressed registers the players into a [lottery.NewCompressedRegistry], which only keeps distinct
+	// combinations. Reports are the same, but players cannot be broken down by metadata.
+	Compressed bool
+
+	// Dimensions are additional dimension columns of CSV files, beyond those of [DefaultCSVColumns], eg: the region
+	// or county each ticket was sold in. Every CSV file must have these columns.
+	Dimensions []CSVDimension
+
+	// KeepAccounts keeps the account ID of every ticket loaded from CSV files, under the [AccountAttribute] of the
+	// metadata, eg: for exporting the winners. Disabled by default, since it takes memory for every player.
+	KeepAccounts bool
+
+	// KeepTicketIDs keeps the explicit ticket ID of every ticket loaded from CSV files, under the [TicketAttribute] of
+	// the metadata, eg: for exporting the winners. Disabled by default, since it takes memory for every player.
+	KeepTicketIDs bool
+
+	// DiskDirectory, if given, registers the players into a [lottery.NewDiskRegistry], which keeps its buckets in
+	// files under this directory, for ticket volumes beyond memory. Ignored if Compressed is enabled. The registry
+	// should be closed once no longer needed, to remove its files.
+	DiskDirectory string
+}
+
+// game returns the game of the tickets, which defaults to [lottery.Otoslotto].
+func (o LoadOptions) game() lottery.Game {
+	if o.Game == (lottery.Game{}) {
+		return lottery.Otoslotto
+	}
+	return o.Game
+}
+
+// csvColumns returns the column mapping of CSV files, i.e., the [DefaultCSVColumns] along with the additional
+// dimensions.
+func (o LoadOptions) csvColumns() CSVColumns {
+	columns := DefaultCSVColumns
+	columns.Dimensions = append(slices.Clone(columns.Dimensions), o.Dimensions...)
+	return columns
+}
+
+// LoadSummary summarizes the outcome of loading a file.
+type LoadSummary struct {
+	// Lines is the total number of lines in the file, valid or not. For CSV files, this is the number of records,
+	// excluding the header.
+	Lines int
+
+	// Players is the number of registered players, i.e., the number of valid lines.
+	Players int
+
+	// Rejections counts the rejected lines per kind of error, for example [ErrNumberOutOfRange].
+	Rejections map[error]int
+
+	// Metadata holds the dimensions of the loaded players, such as the [SourceDimension] when loading from multiple
+	// files with [LoadFiles], or the dimension columns of CSV files. Nil if there are no dimensions.
+	Metadata *lottery.Metadata
+
+	// Combinations counts the loaded tickets per combination, and per source file. Only available if
+	// [LoadOptions.IndexCombinations] was enabled.
+	Combinations *lottery.CombinationIndex
+}
+
+// Rejected returns the total number of rejected lines.
+func (s LoadSummary) Rejected() int {
+	total := 0
+	for _, count := range s.Rejections {
+		total += count
+	}
+	return total
+}
+
+// String formats the summary for textual representation, listing rejections in a deterministic order.
+func (s LoadSummary) String() string {
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf("%v players loaded from %v lines, %v rejected", s.Players, s.Lines, s.Rejected()))
+
+	counts := make(map[string]int, len(s.Rejections))
+	for err, count := range s.Rejections {
+		counts[err.Error()] += count
+	}
+
+	reasons := make([]string, 0, len(counts))
+	for reason := range counts {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+
+	for _, reason := range reasons {
+		output.WriteString(fmt.Sprintf("; %v: %v", reason, counts[reason]))
+	}
+
+	return output.String()
+}
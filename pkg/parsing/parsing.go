@@ -2,8 +2,12 @@ package parsing
 
 import (
 	"bufio"
+	"context"
 	"errors"
+	"fmt"
+	"io"
 	"os"
+	"runtime"
 	"strconv"
 	"strings"
 
@@ -13,89 +17,253 @@ import (
 )
 
 // LoadFile parses a file and fills the player picks into a new [lottery.Registry] instance.
-// For efficiency purposes, first the file is traversed so that we can determine the allocation
-// necessary to represent the player picks. Then, the file is read again to register the player picks.
-// This was done to avoid the unnecessary overhead from resizing the underlying arrays during slice appends.
-func LoadFile(fileName string) (lottery.Registry, error) {
-	allocation, err := determineNumberAllocation(fileName)
-	if err != nil {
-		return nil, err
-	}
+// For efficiency purposes, the file is split into byte ranges aligned on line boundaries, which are parsed
+// concurrently. The number allocations of each range are then merged, so that the player picks can be registered
+// without the unnecessary overhead from resizing the underlying arrays during slice appends.
+// Player IDs are assigned sequentially to the valid lines, in the same order they appear in the file.
+// Invalid lines are handled according to the given [LoadOptions], and summarized in the returned [LoadSummary].
+// Under the [Strict] policy, an error wrapping [ErrRejectedLines] is returned if any line was rejected.
+func LoadFile(fileName string, options LoadOptions) (lottery.Registry, LoadSummary, error) {
+	return loadFileInChunks(context.Background(), fileName, options, defaultNumChunks())
+}
 
-	registry := lottery.NewRegistryFromNumberAllocation(allocation)
+// defaultNumChunks splits text files in one chunk per CPU, so that all of them are busy parsing.
+func defaultNumChunks() int {
+	return runtime.NumCPU()
+}
 
-	if err = registerPlayers(fileName, registry); err != nil {
-		return nil, err
+func loadFileInChunks(
+	ctx context.Context, fileName string, options LoadOptions, numChunks int,
+) (lottery.Registry, LoadSummary, error) {
+	chunks, err := parseTextFile(ctx, fileName, options.game(), numChunks)
+	if err != nil {
+		return nil, LoadSummary{}, err
 	}
 
-	return registry, nil
+	return buildRegistry(ctx, chunks, options, nil)
 }
 
-func determineNumberAllocation(fileName string) ([]int, error) {
+// parseTextFile splits a text file into, at most, numChunks chunks, and parses them concurrently, as tickets of the
+// given game.
+func parseTextFile(ctx context.Context, fileName string, game lottery.Game, numChunks int) ([]*chunk, error) {
 	file, err := os.Open(fileName)
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = file.Close() }()
 
-	numberAllocation := make([]int, lottery.MaxNumber)
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
 
-	scanner := bufio.NewScanner(file)
-	picks := make([]lottery.Number, lottery.NumPicks)
+	chunks, err := splitIntoChunks(file, info.Size(), numChunks)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range chunks {
+		c.game = game
+	}
 
-	for scanner.Scan() {
-		if err = ParseLine(scanner.Text(), picks); err != nil {
-			continue
-		}
+	if err = parseChunks(ctx, file, chunks); err != nil {
+		return nil, err
+	}
+
+	return chunks, nil
+}
+
+// buildRegistry handles the rejected lines of the parsed chunks according to the given options, then merges their
+// number allocations and registers their player picks into a new [lottery.Registry]. The metadata, if any, is
+// returned in the summary. If the chunks come from multiple sources, each player is labeled by its source.
+// Registration is abandoned if the context is done.
+func buildRegistry(
+	ctx context.Context, chunks []*chunk, options LoadOptions, metadata *lottery.Metadata,
+) (lottery.Registry, LoadSummary, error) {
+	game := options.game()
+	if game != lottery.Otoslotto && (options.Compressed || options.DiskDirectory != "" || options.IndexCombinations) {
+		return nil, LoadSummary{}, fmt.Errorf("%w: %v tickets can only be loaded into a regular registry",
+			ErrUnsupportedGame, game.Name)
+	}
+
+	summary, err := reviewRejections(chunks, options)
+	if err != nil {
+		return nil, summary, err
+	}
+
+	var sources *lottery.Dimension
+	if metadata != nil && len(chunks) > 0 && chunks[0].source != "" {
+		sources = metadata.Dimension(SourceDimension)
+	}
+	summary.Metadata = metadata
 
-		for _, pick := range picks {
-			numberAllocation[pick-1]++
+	var registry lottery.Registry
+	if options.Compressed {
+		registry = lottery.NewCompressedRegistry(summary.Players)
+	} else if options.DiskDirectory != "" {
+		if registry, err = lottery.NewDiskRegistry(options.DiskDirectory, lottery.DefaultWindowSize); err != nil {
+			return nil, summary, err
 		}
+	} else {
+		allocation := make([]int, game.MaxNumber)
+		for _, c := range chunks {
+			for i := range allocation {
+				allocation[i] += c.allocation[i]
+			}
+		}
+		registry = lottery.NewGameRegistry(game, allocation)
 	}
 
-	if err = scanner.Err(); err != nil {
-		return nil, err
+	if err = registerPlayers(ctx, chunks, registry, sources, game); err != nil {
+		closeRegistry(registry)
+		return nil, summary, err
+	}
+	if failing, ok := registry.(interface{ Err() error }); ok && failing.Err() != nil {
+		err = failing.Err()
+		closeRegistry(registry)
+		return nil, summary, err
+	}
+
+	if options.IndexCombinations {
+		summary.Combinations = indexCombinations(chunks)
 	}
 
-	return numberAllocation, nil
+	return registry, summary, nil
 }
 
-func registerPlayers(fileName string, registry lottery.Registry) error {
-	file, err := os.Open(fileName)
-	if err != nil {
-		return err
+// closeRegistry closes a registry which is no longer needed, if it holds resources such as files.
+func closeRegistry(registry lottery.Registry) {
+	if closer, ok := registry.(io.Closer); ok {
+		_ = closer.Close()
 	}
-	defer func() { _ = file.Close() }()
+}
+
+// reviewRejections handles the rejected lines of the parsed chunks, and summarizes them. Under the [Strict] policy,
+// an error is returned if any line was rejected.
+func reviewRejections(chunks []*chunk, options LoadOptions) (LoadSummary, error) {
+	summary := LoadSummary{Rejections: make(map[error]int)}
+
+	if err := handleRejections(chunks, options, &summary); err != nil {
+		return summary, err
+	}
+
+	for _, c := range chunks {
+		summary.Players += len(c.picks) / options.game().NumPicks
+	}
+
+	if options.Policy == Strict && summary.Rejected() > 0 {
+		return summary, fmt.Errorf("%w: %v of %v lines", ErrRejectedLines, summary.Rejected(), summary.Lines)
+	}
+
+	return summary, nil
+}
 
-	lineNumber := 1
+// handleRejections warns about the rejected lines of all chunks, in the order they appear in the file, and writes
+// them to the quarantine file, if any. The line numbers of each chunk are relative to its beginning, so they are
+// offset by the lines of all chunks of the same source that come before it.
+func handleRejections(chunks []*chunk, options LoadOptions, summary *LoadSummary) (err error) {
+	var quarantine *bufio.Writer
+	if options.QuarantineFile != "" {
+		var file *os.File
+		if file, err = os.Create(options.QuarantineFile); err != nil {
+			return err
+		}
+		defer func() {
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+		quarantine = bufio.NewWriter(file)
+	}
+
+	lineOffset := 0
+	for i, c := range chunks {
+		if i > 0 && c.source != chunks[i-1].source {
+			lineOffset = 0
+		}
+
+		for _, r := range c.rejections {
+			r.err.Source = c.source
+			r.err.Line = lineOffset + r.line
+			summary.Rejections[r.err.Err]++
+			log.Warnf("skipping %v — '%v'", r.err, r.content)
+
+			if quarantine != nil {
+				if _, err = fmt.Fprintf(quarantine, "%v\t%v\t%v\n", r.err.Location(), r.err.Reason(), r.content); err != nil {
+					return err
+				}
+			}
+		}
+
+		lineOffset += c.lines
+		summary.Lines += c.lines
+	}
+
+	if quarantine != nil {
+		return quarantine.Flush()
+	}
+
+	return nil
+}
+
+// registerPlayers registers the player picks of all chunks, in the order they appear in the file. Player IDs are
+// assigned sequentially, regardless of the explicit ticket IDs of CSV files, which may be arbitrarily large and
+// sparse, so that arrays indexed by player ID never grow beyond the number of players. If sources is given, each
+// player is assigned the source of its chunk. Gives up, between chunks, if the context is done.
+func registerPlayers(
+	ctx context.Context, chunks []*chunk, registry lottery.Registry, sources *lottery.Dimension, game lottery.Game,
+) error {
 	var playerID lottery.PlayerID = 1
-	scanner := bufio.NewScanner(file)
-	picks := make([]lottery.Number, lottery.NumPicks)
-
-	for scanner.Scan() {
-		line := scanner.Text()
-		if err = ParseLine(line, picks); err != nil {
-			log.Warnf("skipping line %v: %v — '%v'", lineNumber, err, line)
-			lineNumber++
-			continue
+
+	for _, c := range chunks {
+		if err := lottery.ContextError(ctx); err != nil {
+			return err
+		}
+
+		first := playerID
+		for i := 0; i < len(c.picks); i += game.NumPicks {
+			registry.RegisterPlayer(playerID, c.picks[i:i+game.NumPicks])
+			playerID++
 		}
 
-		registry.RegisterPlayer(playerID, picks)
-		lineNumber++
-		playerID++
+		if sources != nil && playerID > first {
+			sources.AssignRange(first, playerID-1, c.source)
+		}
 	}
 
-	return scanner.Err()
+	return nil
+}
+
+// indexCombinations counts the player picks of all chunks per combination, and per source.
+func indexCombinations(chunks []*chunk) *lottery.CombinationIndex {
+	index := lottery.NewCombinationIndex()
+	for _, c := range chunks {
+		for i := 0; i < len(c.picks); i += lottery.NumPicks {
+			index.Add(c.picks[i:i+lottery.NumPicks], c.source)
+		}
+	}
+	return index
 }
 
 // ParseLine parses a textual line representing the picked lottery numbers.
 // The numbers must be separated by whitespace, as defined by [unicode.IsSpace].
 // A fixed quantity of [lottery.NumPicks] should be given.
 // All numbers should be between 1 and [lottery.MaxNumber], inclusive.
+// If the line is invalid, a [*ParseError] is returned. Its line number is unknown, and left for the caller to fill.
 func ParseLine(line string, picks []lottery.Number) error {
-	fields := strings.Fields(line)
+	return parseFields(lottery.Otoslotto, strings.Fields(line), picks)
+}
+
+// ParseGameLine parses a textual line representing the picked lottery numbers of the given game, the same way as
+// [ParseLine] does, except that picks must hold the number of picks of the game, and numbers must be between 1 and
+// the maximum number of the game, inclusive.
+func ParseGameLine(game lottery.Game, line string, picks []lottery.Number) error {
+	return parseFields(game, strings.Fields(line), picks)
+}
+
+// parseFields parses and validates each field as a picked lottery number of the game, as described by [ParseLine].
+func parseFields(game lottery.Game, fields []string, picks []lottery.Number) error {
 	if len(fields) != len(picks) {
-		return ErrInvalidQuantityOfNumbers
+		return &ParseError{Err: ErrInvalidQuantityOfNumbers}
 	}
 
 	for i := 0; i < len(picks); i++ {
@@ -103,25 +271,38 @@ func ParseLine(line string, picks []lottery.Number) error {
 		parsed, err := strconv.ParseInt(field, 10, lottery.NumberBitSize)
 		if err != nil {
 			if errors.Is(err, strconv.ErrRange) {
-				return ErrNumberOutOfRange
+				return &ParseError{Field: i + 1, Token: field, Err: ErrNumberOutOfRange}
 			}
-			return err
+			return &ParseError{Field: i + 1, Token: field, Err: ErrNotANumber}
 		}
-		pick := lottery.Number(parsed)
-
-		if pick > lottery.MaxNumber || pick < 1 {
-			return ErrNumberOutOfRange
+		if parsed < 1 || parsed > int64(game.MaxNumber) {
+			return &ParseError{Field: i + 1, Token: field, Err: ErrNumberOutOfRange}
 		}
 
-		picks[i] = pick
+		picks[i] = lottery.Number(parsed)
 	}
 
-	for i := 0; i < lottery.NumPicks; i++ {
-		for j := 0; j < lottery.NumPicks; j++ {
-			if i != j {
-				if picks[i] == picks[j] {
-					return ErrNoRepeatedNumbers
-				}
+	if err := validatePicks(game, picks); err != nil {
+		err.Token = fields[err.Field-1]
+		return err
+	}
+
+	return nil
+}
+
+// validatePicks checks that all picks are between 1 and the maximum number of the game, inclusive, and that there are
+// no repeated picks. The token of the returned error is the offending pick, formatted as a number.
+func validatePicks(game lottery.Game, picks []lottery.Number) *ParseError {
+	for i, pick := range picks {
+		if int(pick) > game.MaxNumber || pick < 1 {
+			return &ParseError{Field: i + 1, Token: strconv.Itoa(int(pick)), Err: ErrNumberOutOfRange}
+		}
+	}
+
+	for i := 0; i < len(picks); i++ {
+		for j := i + 1; j < len(picks); j++ {
+			if picks[i] == picks[j] {
+				return &ParseError{Field: j + 1, Token: strconv.Itoa(int(picks[j])), Err: ErrNoRepeatedNumbers}
 			}
 		}
 	}
@@ -1,7 +1,13 @@
 package parsing
 
 import (
+	"context"
+	"io"
+	"os"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -84,8 +90,78 @@ func TestParseLineFailIfNumbersAreRepeated(t *testing.T) {
 	assert.ErrorIs(t, err, ErrNoRepeatedNumbers)
 }
 
+func TestParseLineFailIfNotNumeric(t *testing.T) {
+	picks := make([]lottery.Number, 5)
+
+	err := ParseLine("88 28 abc 72 14", picks)
+	assert.ErrorIs(t, err, ErrNotANumber)
+
+	var parseError *ParseError
+	assert.ErrorAs(t, err, &parseError)
+	assert.Equal(t, 3, parseError.Field)
+	assert.Equal(t, "abc", parseError.Token)
+	assert.Equal(t, "field 3 'abc': picked number is not numeric", err.Error())
+}
+
+func TestParseLineFailWithPositionOfOffendingField(t *testing.T) {
+	picks := make([]lottery.Number, 5)
+	var parseError *ParseError
+
+	err := ParseLine("88 28 91 72 14", picks)
+	assert.ErrorAs(t, err, &parseError)
+	assert.Equal(t, &ParseError{Field: 3, Token: "91", Err: ErrNumberOutOfRange}, parseError)
+
+	err = ParseLine("10 20 30 20 50", picks)
+	assert.ErrorAs(t, err, &parseError)
+	assert.Equal(t, &ParseError{Field: 4, Token: "20", Err: ErrNoRepeatedNumbers}, parseError)
+
+	err = ParseLine("10 20 30", picks)
+	assert.ErrorAs(t, err, &parseError)
+	assert.Equal(t, &ParseError{Err: ErrInvalidQuantityOfNumbers}, parseError)
+}
+
+func TestParseErrorWithLineNumber(t *testing.T) {
+	err := &ParseError{Line: 12, Field: 5, Token: "100", Err: ErrNumberOutOfRange}
+	assert.Equal(t, "line 12: field 5 '100': picked number is out of range", err.Error())
+
+	err = &ParseError{Line: 7, Err: ErrInvalidQuantityOfNumbers}
+	assert.Equal(t, "line 7: invalid quantity of picked numbers", err.Error())
+}
+
+// FuzzParseLine checks that any line is either rejected with a [*ParseError], or parsed into valid picks, which
+// parse again into the same picks once formatted.
+func FuzzParseLine(f *testing.F) {
+	for _, line := range []string{
+		"88 28 43 72 14", " 7\t64  80 90 58 ", "88 28 91 72 14", "88 28 28 72 14", "0 1 2 3 4", "-1 2 3 4 5",
+		"1 2 3 4", "1 2 3 4 5 6", "", "a b c d e", "+1 02 3 4 5", "1 2 3 4 5\r", "1\u00a02 3 4 5 6",
+	} {
+		f.Add(line)
+	}
+
+	f.Fuzz(func(t *testing.T, line string) {
+		picks := make([]lottery.Number, lottery.NumPicks)
+
+		err := ParseLine(line, picks)
+		if err != nil {
+			var parseError *ParseError
+			assert.ErrorAs(t, err, &parseError)
+			return
+		}
+
+		assert.Nil(t, validatePicks(lottery.Otoslotto, picks))
+
+		fields := make([]string, 0, lottery.NumPicks)
+		for _, pick := range picks {
+			fields = append(fields, strconv.Itoa(int(pick)))
+		}
+		reparsed := make([]lottery.Number, lottery.NumPicks)
+		assert.NoError(t, ParseLine(strings.Join(fields, " "), reparsed))
+		assert.Equal(t, picks, reparsed)
+	})
+}
+
 func TestLoadPlayerPicksFromFile(t *testing.T) {
-	registry, err := LoadFile("testdata/1k-players.txt")
+	registry, _, err := LoadFile("testdata/1k-players.txt", LoadOptions{})
 	assert.NoError(t, err)
 
 	assert.True(t, registry.HasPlayerPick(14, 12))
@@ -111,7 +187,7 @@ func TestLoadPlayerPicksFromFile(t *testing.T) {
 }
 
 func TestLoadPlayerPicksFromFileWithoutNewlineAtEnd(t *testing.T) {
-	registry, err := LoadFile("testdata/1k-players_no-newline-at-end.txt")
+	registry, _, err := LoadFile("testdata/1k-players_no-newline-at-end.txt", LoadOptions{})
 	assert.NoError(t, err)
 
 	assert.True(t, registry.HasPlayerPick(14, 12))
@@ -137,7 +213,7 @@ func TestLoadPlayerPicksFromFileWithoutNewlineAtEnd(t *testing.T) {
 }
 
 func TestLoadPlayerPicksFromFileSkippingBogusLines(t *testing.T) {
-	registry, err := LoadFile("testdata/bogus.txt")
+	registry, _, err := LoadFile("testdata/bogus.txt", LoadOptions{})
 	assert.NoError(t, err)
 
 	assert.True(t, registry.HasPlayerPick(14, 12))
@@ -147,3 +223,141 @@ func TestLoadPlayerPicksFromFileSkippingBogusLines(t *testing.T) {
 	assert.True(t, registry.HasPlayerPick(14, 32))
 	assert.False(t, registry.HasPlayerPick(14, 10))
 }
+
+func TestLoadPlayerPicksFromFileIntoCompressedRegistry(t *testing.T) {
+	regular, _, err := LoadFile("testdata/bogus.txt", LoadOptions{})
+	assert.NoError(t, err)
+	compressed, summary, err := LoadFile("testdata/bogus.txt", LoadOptions{Compressed: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 995, summary.Players)
+
+	regular.BeReadyForProcessing()
+	compressed.BeReadyForProcessing()
+
+	for _, picks := range [][]lottery.Number{
+		{12, 83, 73, 26, 32},
+		{71, 66, 86, 4, 50},
+		{1, 2, 3, 4, 5},
+	} {
+		assert.Equal(t, regular.ProcessLotteryPicks(picks).String(), compressed.ProcessLotteryPicks(picks).String())
+		regular.ResetLastProcessing()
+		compressed.ResetLastProcessing()
+	}
+}
+
+func TestLoadPlayerPicksFromFileIntoDiskRegistry(t *testing.T) {
+	regular, _, err := LoadFile("testdata/bogus.txt", LoadOptions{})
+	assert.NoError(t, err)
+	disk, summary, err := LoadFile("testdata/bogus.txt", LoadOptions{DiskDirectory: t.TempDir()})
+	assert.NoError(t, err)
+	defer func() { assert.NoError(t, disk.(io.Closer).Close()) }()
+	assert.Equal(t, 995, summary.Players)
+
+	regular.BeReadyForProcessing()
+	disk.BeReadyForProcessing()
+
+	for _, picks := range [][]lottery.Number{
+		{12, 83, 73, 26, 32},
+		{71, 66, 86, 4, 50},
+		{1, 2, 3, 4, 5},
+	} {
+		assert.Equal(t, regular.ProcessLotteryPicks(picks).String(), disk.ProcessLotteryPicks(picks).String())
+		regular.ResetLastProcessing()
+		disk.ResetLastProcessing()
+	}
+}
+
+func TestLoadPlayerPicksOfAnotherGame(t *testing.T) {
+	fileName := t.TempDir() + "/hatoslotto.txt"
+	assert.NoError(t, os.WriteFile(fileName, []byte("1 2 3 4 5 6\n1 2 3 4 5 45\n1 2 3 4 5 46\n1 2 3 4 5\n"), 0o644))
+
+	registry, summary, err := Load(fileName, LoadOptions{Game: lottery.Hatoslotto})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, summary.Players)
+	assert.Equal(t, map[error]int{ErrNumberOutOfRange: 1, ErrInvalidQuantityOfNumbers: 1}, summary.Rejections)
+
+	registry.BeReadyForProcessing()
+	report := registry.ProcessLotteryPicks([]lottery.Number{1, 2, 3, 4, 5, 6})
+	assert.Equal(t, "0 0 0 1 1", report.String())
+
+	_, _, err = Load(fileName, LoadOptions{Game: lottery.Hatoslotto, Compressed: true})
+	assert.ErrorIs(t, err, ErrUnsupportedGame)
+	_, _, err = Load("testdata/tickets.csv", LoadOptions{Game: lottery.Hatoslotto})
+	assert.ErrorIs(t, err, ErrUnsupportedGame)
+}
+
+func TestParseGameLine(t *testing.T) {
+	picks := make([]lottery.Number, lottery.Skandinav.NumPicks)
+
+	assert.NoError(t, ParseGameLine(lottery.Skandinav, "35 1 2 3 4 5 6", picks))
+	assert.Equal(t, []lottery.Number{35, 1, 2, 3, 4, 5, 6}, picks)
+
+	assert.ErrorIs(t, ParseGameLine(lottery.Skandinav, "36 1 2 3 4 5 6", picks), ErrNumberOutOfRange)
+	assert.ErrorIs(t, ParseGameLine(lottery.Skandinav, "1 2 3 4 5", picks), ErrInvalidQuantityOfNumbers)
+}
+
+func TestLoadPlayerPicksFromFileCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for _, fileName := range []string{"testdata/1k-players.txt", "testdata/tickets.csv"} {
+		registry, _, err := LoadContext(ctx, fileName, LoadOptions{})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, registry)
+	}
+}
+
+func TestLoadPlayerPicksFromFileFailIfDeadlineExceeded(t *testing.T) {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	_, _, err := LoadContext(ctx, "testdata/1k-players.txt", LoadOptions{})
+	assert.ErrorIs(t, err, lottery.ErrDeadlineExceeded)
+
+	_, _, err = LoadFilesContext(ctx, []string{"testdata/1k-players.txt", "testdata/bogus.txt"}, LoadOptions{})
+	assert.ErrorIs(t, err, lottery.ErrDeadlineExceeded)
+}
+
+func TestLoadPlayerPicksFromFileSummarizingRejections(t *testing.T) {
+	_, summary, err := LoadFile("testdata/bogus.txt", LoadOptions{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1000, summary.Lines)
+	assert.Equal(t, 995, summary.Players)
+	assert.Equal(t, 5, summary.Rejected())
+	assert.Equal(t, map[error]int{ErrInvalidQuantityOfNumbers: 3, ErrNumberOutOfRange: 2}, summary.Rejections)
+	assert.Equal(t, "995 players loaded from 1000 lines, 5 rejected; "+
+		"invalid quantity of picked numbers: 3; picked number is out of range: 2", summary.String())
+}
+
+func TestLoadPlayerPicksFromFileFailIfStrictAndHasBogusLines(t *testing.T) {
+	registry, summary, err := LoadFile("testdata/bogus.txt", LoadOptions{Policy: Strict})
+	assert.ErrorIs(t, err, ErrRejectedLines)
+	assert.Nil(t, registry)
+	assert.Equal(t, 5, summary.Rejected())
+}
+
+func TestLoadPlayerPicksFromFileSucceedIfStrictAndHasNoBogusLines(t *testing.T) {
+	registry, summary, err := LoadFile("testdata/1k-players.txt", LoadOptions{Policy: Strict})
+	assert.NoError(t, err)
+	assert.NotNil(t, registry)
+	assert.Equal(t, 1000, summary.Players)
+	assert.Equal(t, 0, summary.Rejected())
+}
+
+func TestLoadPlayerPicksFromFileWritingQuarantine(t *testing.T) {
+	quarantineFile := t.TempDir() + "/quarantine.tsv"
+
+	_, _, err := LoadFile("testdata/bogus.txt", LoadOptions{QuarantineFile: quarantineFile})
+	assert.NoError(t, err)
+
+	contents, err := os.ReadFile(quarantineFile)
+	assert.NoError(t, err)
+	assert.Equal(t, ""+
+		"728\tinvalid quantity of picked numbers\t34 65 21 59 48 50\n"+
+		"737\tinvalid quantity of picked numbers\t49 25 11 22\n"+
+		"743\tfield 5 '100': picked number is out of range\t30 62 32 8 100\n"+
+		"813\tfield 5 '1000': picked number is out of range\t15 21 84 40 1000\n"+
+		"883\tinvalid quantity of picked numbers\t24 18 37\n",
+		string(contents))
+}
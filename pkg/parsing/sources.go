@@ -0,0 +1,106 @@
+package parsing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+)
+
+// SourceDimension is the name of the [lottery.Dimension] labeling each player with the file it was loaded from.
+const SourceDimension = "source"
+
+// LoadFiles loads several ticket files, of any supported format, into a single new [lottery.Registry] instance.
+// Tickets usually arrive from several sales channels as separate files.
+// Player IDs are assigned sequentially across all files, in the given order. Explicit ticket IDs from CSV files must
+// be unique across all of them, and are kept as described by [LoadCSVFile].
+// The metadata of the returned [LoadSummary] labels each player with its source file, under the [SourceDimension],
+// so that reports can be broken down by it.
+func LoadFiles(fileNames []string, options LoadOptions) (lottery.Registry, LoadSummary, error) {
+	return LoadFilesContext(context.Background(), fileNames, options)
+}
+
+// LoadFilesContext loads several ticket files as [LoadFiles] does, but gives up as soon as the context is done, eg: on
+// shutdown, with [lottery.ErrDeadlineExceeded] if its deadline was hit, or [context.Canceled].
+func LoadFilesContext(
+	ctx context.Context, fileNames []string, options LoadOptions,
+) (lottery.Registry, LoadSummary, error) {
+	var chunks []*chunk
+	seen := &ticketIDSet{}
+	metadata := lottery.NewMetadata()
+	metadata.Dimension(SourceDimension)
+	keepAttributes(metadata, options)
+
+	//
+	// Player IDs are assigned by registration, after all files are parsed, but CSV files are labeled while being
+	// parsed, so we keep track of the player ID of the first ticket of each file.
+	//
+	var first lottery.PlayerID = 1
+
+	for _, fileName := range fileNames {
+		format, err := DetectFormat(fileName)
+		if err != nil {
+			return nil, LoadSummary{}, fmt.Errorf("%v: %w", fileName, err)
+		}
+
+		fileChunks, err := parseFile(ctx, fileName, format, options, first, seen, metadata)
+		if err != nil {
+			return nil, LoadSummary{}, fmt.Errorf("%v: %w", fileName, err)
+		}
+
+		for _, c := range fileChunks {
+			c.source = fileName
+			first += lottery.PlayerID(len(c.picks) / options.game().NumPicks)
+		}
+		chunks = append(chunks, fileChunks...)
+	}
+
+	return buildRegistry(ctx, chunks, options, metadata)
+}
+
+// ExpandSources expands each of the given paths into ticket files. A path may be a file, a directory, from which all
+// regular files are taken, or a glob pattern, as supported by [filepath.Match]. Files within a directory or matching
+// a pattern are sorted by name. Hidden files are ignored, unless given explicitly.
+func ExpandSources(paths []string) ([]string, error) {
+	var fileNames []string
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		switch {
+		case err == nil && info.IsDir():
+			entries, err := os.ReadDir(path)
+			if err != nil {
+				return nil, err
+			}
+			for _, entry := range entries {
+				if entry.Type().IsRegular() && !strings.HasPrefix(entry.Name(), ".") {
+					fileNames = append(fileNames, filepath.Join(path, entry.Name()))
+				}
+			}
+
+		case err == nil:
+			fileNames = append(fileNames, path)
+
+		default:
+			matches, globErr := filepath.Glob(path)
+			if globErr != nil {
+				return nil, globErr
+			}
+			if len(matches) == 0 {
+				return nil, err
+			}
+			sort.Strings(matches)
+			fileNames = append(fileNames, matches...)
+		}
+	}
+
+	if len(fileNames) == 0 {
+		return nil, ErrNoSources
+	}
+
+	return fileNames, nil
+}
@@ -0,0 +1,161 @@
+package parsing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+)
+
+func TestLoadPlayerPicksFromMultipleFiles(t *testing.T) {
+	fileNames := []string{"testdata/1k-players.txt", "testdata/tickets.csv", "testdata/bogus.txt"}
+
+	registry, summary, err := LoadFiles(fileNames, LoadOptions{KeepTicketIDs: true})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1000+995+4, summary.Players)
+	assert.Equal(t, 1000+9+1000, summary.Lines)
+	assert.Equal(t, 5+5, summary.Rejected())
+
+	//
+	// Player IDs are sequential across all files, regardless of the ticket IDs in the CSV file.
+	//
+	assert.True(t, registry.HasPlayerPick(14, 12))
+	assert.True(t, registry.HasPlayerPick(14, 32))
+	assert.True(t, registry.HasPlayerPick(1001, 12))
+	assert.True(t, registry.HasPlayerPick(1004, 44))
+	assert.True(t, registry.HasPlayerPick(1004+14, 83))
+	assert.False(t, registry.HasPlayerPick(1004+14, 10))
+
+	sources, ok := summary.Metadata.Lookup(SourceDimension)
+	assert.True(t, ok)
+	assert.Equal(t, fileNames, sources.Labels())
+	assert.Equal(t, "testdata/1k-players.txt", sources.LabelOf(1))
+	assert.Equal(t, "testdata/1k-players.txt", sources.LabelOf(1000))
+	assert.Equal(t, "testdata/tickets.csv", sources.LabelOf(1001))
+	assert.Equal(t, "testdata/tickets.csv", sources.LabelOf(1004))
+	assert.Equal(t, "testdata/bogus.txt", sources.LabelOf(1005))
+	assert.Equal(t, "testdata/bogus.txt", sources.LabelOf(1999))
+	assert.Equal(t, "", sources.LabelOf(2000))
+
+	//
+	// Only tickets from the CSV file have explicit ticket IDs, and labels are assigned to their player IDs.
+	//
+	tickets, ok := summary.Metadata.LookupAttribute(TicketAttribute)
+	assert.True(t, ok)
+	assert.Equal(t, "1001", tickets.ValueOf(1001))
+	assert.Equal(t, "2000", tickets.ValueOf(1004))
+	assert.Equal(t, "", tickets.ValueOf(14))
+	assert.Equal(t, "", tickets.ValueOf(1005))
+
+	channel, _ := summary.Metadata.Lookup("channel")
+	assert.Equal(t, "mobile", channel.LabelOf(1004))
+	assert.Equal(t, "", channel.LabelOf(2000))
+}
+
+func TestLoadPlayerPicksFromMultipleFilesBrokenDownBySource(t *testing.T) {
+	fileNames := []string{"testdata/1k-players.txt", "testdata/tickets.csv", "testdata/bogus.txt"}
+
+	registry, summary, err := LoadFiles(fileNames, LoadOptions{})
+	assert.NoError(t, err)
+	registry.BeReadyForProcessing()
+
+	report := registry.ProcessLotteryPicks([]lottery.Number{12, 83, 73, 26, 32})
+	sources, _ := summary.Metadata.Lookup(SourceDimension)
+	breakdown := lottery.BreakDown(registry.(lottery.MatchVisitor), sources, summary.Players)
+	registry.ResetLastProcessing()
+
+	assert.Equal(t, "source", breakdown.Dimension)
+	assert.Equal(t, fileNames, breakdown.Labels)
+
+	for matches := 0; matches <= lottery.NumPicks; matches++ {
+		total := 0
+		for _, label := range breakdown.Labels {
+			total += breakdown.Reports[label].GetTicketsHaving(matches)
+		}
+		assert.Equal(t, report.GetTicketsHaving(matches), total)
+	}
+
+	assert.Equal(t, 1, breakdown.Reports["testdata/tickets.csv"].GetWinnersHaving(5))
+	assert.Equal(t, 1, breakdown.Reports["testdata/1k-players.txt"].GetWinnersHaving(5))
+	assert.Equal(t, 1, breakdown.Reports["testdata/bogus.txt"].GetWinnersHaving(5))
+}
+
+func TestLoadPlayerPicksFromMultipleFilesWritingQuarantine(t *testing.T) {
+	quarantineFile := t.TempDir() + "/quarantine.tsv"
+	fileNames := []string{"testdata/1k-players.txt", "testdata/bogus.txt"}
+
+	_, _, err := LoadFiles(fileNames, LoadOptions{QuarantineFile: quarantineFile})
+	assert.NoError(t, err)
+
+	contents, err := os.ReadFile(quarantineFile)
+	assert.NoError(t, err)
+	assert.Contains(t, string(contents), "testdata/bogus.txt:728\tinvalid quantity of picked numbers\t34 65 21 59 48 50\n")
+}
+
+func TestLoadPlayerPicksFromMultipleFilesFailIfTicketIDsOverlap(t *testing.T) {
+	fileNames := []string{"testdata/tickets.csv", "testdata/tickets.csv"}
+
+	_, summary, err := LoadFiles(fileNames, LoadOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, summary.Players)
+	assert.Equal(t, 5+9, summary.Rejected())
+}
+
+func TestExpandSources(t *testing.T) {
+	directory := t.TempDir()
+	for _, name := range []string{"b.txt", "a.txt", "c.csv", ".hidden"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(directory, name), nil, 0o600))
+	}
+	assert.NoError(t, os.Mkdir(filepath.Join(directory, "nested"), 0o700))
+
+	fileNames, err := ExpandSources([]string{directory})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(directory, "a.txt"),
+		filepath.Join(directory, "b.txt"),
+		filepath.Join(directory, "c.csv"),
+	}, fileNames)
+
+	fileNames, err = ExpandSources([]string{filepath.Join(directory, "*.txt"), "testdata/tickets.csv"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(directory, "a.txt"),
+		filepath.Join(directory, "b.txt"),
+		"testdata/tickets.csv",
+	}, fileNames)
+
+	_, err = ExpandSources([]string{filepath.Join(directory, "*.bin")})
+	assert.Error(t, err)
+}
+
+func TestLoadPlayerPicksFromMultipleFilesIndexingCombinations(t *testing.T) {
+	fileNames := []string{"testdata/1k-players.txt", "testdata/bogus.txt"}
+
+	_, summary, err := LoadFiles(fileNames, LoadOptions{IndexCombinations: true})
+	assert.NoError(t, err)
+
+	index := summary.Combinations
+	assert.Equal(t, 2, index.Count([]lottery.Number{12, 83, 73, 26, 32}))
+	assert.Equal(t, 2, index.Count([]lottery.Number{32, 26, 73, 83, 12}))
+	assert.Equal(t, 1, index.Count([]lottery.Number{34, 65, 21, 59, 48}))
+
+	assert.Empty(t, index.SuspiciousDuplicates(2))
+	assert.Equal(t, []lottery.SourceDuplicate{
+		{Combination: lottery.CombinationOf([]lottery.Number{71, 66, 86, 4, 50}), Source: "testdata/1k-players.txt", Count: 1},
+		{Combination: lottery.CombinationOf([]lottery.Number{71, 66, 86, 4, 50}), Source: "testdata/bogus.txt", Count: 1},
+	}, filterDuplicates(index.SuspiciousDuplicates(1), []lottery.Number{71, 66, 86, 4, 50}))
+}
+
+func filterDuplicates(duplicates []lottery.SourceDuplicate, picks []lottery.Number) []lottery.SourceDuplicate {
+	var filtered []lottery.SourceDuplicate
+	for _, duplicate := range duplicates {
+		if duplicate.Combination == lottery.CombinationOf(picks) {
+			filtered = append(filtered, duplicate)
+		}
+	}
+	return filtered
+}
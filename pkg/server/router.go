@@ -0,0 +1,101 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Router hosts several games in one process, eg: all games drawn on the same night, each one served by its own
+// [Server], with its own rules, registry and ticket files, while sharing the same listeners. Every line starts with
+// the name of a game, and the rest of the line is handled by the server of that game. For example, the draw
+// "hatoslotto 3 14 15 29 33 41" is answered with the report of the "hatoslotto" game.
+type Router struct {
+	names   []string
+	servers map[string]*Server
+}
+
+// NewRouter creates a new [Router] without any games.
+func NewRouter() *Router {
+	return &Router{servers: make(map[string]*Server)}
+}
+
+// Handle hosts a game with the given name, served by the given server. Must not be invoked once serving.
+func (r *Router) Handle(name string, s *Server) {
+	if _, ok := r.servers[name]; !ok {
+		r.names = append(r.names, name)
+	}
+	r.servers[name] = s
+}
+
+// Serve accepts connections from the listener, serving draws of all games on each one of them concurrently, until
+// the listener is closed. Each draw is prefixed by the name of its game.
+func (r *Router) Serve(listener net.Listener) error {
+	return serveLines(listener, r.handleDraw)
+}
+
+// ServeAdmin accepts connections from the listener, serving admin commands for all games, until the listener is
+// closed. The commands are the same as [Server.ServeAdmin], except that they are given per game:
+//
+//	RELOAD <game> [path]...  reloads the ticket files of the game, answering with its current paths
+//	STATUS                   answers with the paths of the current ticket files of every game, eg: game=path,path
+func (r *Router) ServeAdmin(listener net.Listener) error {
+	return serveLines(listener, r.handleAdmin)
+}
+
+// Close closes the servers of all games.
+func (r *Router) Close() error {
+	var errs []error
+	for _, name := range r.names {
+		errs = append(errs, r.servers[name].Close())
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Router) handleDraw(line string) string {
+	s, rest, err := r.route(line)
+	if err != nil {
+		return fmt.Sprintf("ERROR %v", err)
+	}
+	return s.handleDraw(rest)
+}
+
+func (r *Router) handleAdmin(line string) string {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return "ERROR missing command"
+	}
+
+	switch strings.ToUpper(fields[0]) {
+	case "RELOAD":
+		s, rest, err := r.route(strings.Join(fields[1:], " "))
+		if err != nil {
+			return fmt.Sprintf("ERROR %v", err)
+		}
+		return s.handleAdmin("RELOAD " + rest)
+	case "STATUS":
+		statuses := make([]string, 0, len(r.names))
+		for _, name := range r.names {
+			statuses = append(statuses, name+"="+strings.Join(r.servers[name].Paths(), ","))
+		}
+		return "OK " + strings.Join(statuses, " ")
+	default:
+		return fmt.Sprintf("ERROR unknown command '%v'", fields[0])
+	}
+}
+
+// route finds the server of the game named by the first field of the line, returning it along with the rest of the
+// line.
+func (r *Router) route(line string) (*Server, string, error) {
+	name, rest, _ := strings.Cut(strings.TrimSpace(line), " ")
+	if name == "" {
+		return nil, "", errors.New("missing game")
+	}
+
+	s, ok := r.servers[name]
+	if !ok {
+		return nil, "", fmt.Errorf("unknown game '%v'", name)
+	}
+	return s, rest, nil
+}
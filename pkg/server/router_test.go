@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+)
+
+func TestRouteDrawsByGame(t *testing.T) {
+	otoslotto := newRegistry([]lottery.Number{11, 22, 33, 44, 55})
+	otoslotto.BeReadyForProcessing()
+
+	hatoslotto := lottery.NewGameRegistry(lottery.Hatoslotto, nil)
+	hatoslotto.RegisterPlayer(1, []lottery.Number{1, 2, 3, 4, 5, 6})
+	hatoslotto.RegisterPlayer(2, []lottery.Number{1, 2, 3, 40, 41, 42})
+	hatoslotto.BeReadyForProcessing()
+
+	router := NewRouter()
+	router.Handle("otoslotto", New(otoslotto, 1))
+	hatoslottoServer := New(hatoslotto, 2)
+	hatoslottoServer.Game = lottery.Hatoslotto
+	router.Handle("hatoslotto", hatoslottoServer)
+
+	exchange(t, listen(t, router.Serve), []string{
+		"otoslotto 11 22 33 44 55", "0 0 0 1",
+		"hatoslotto 1 2 3 4 5 6", "0 1 0 0 1",
+		"hatoslotto 1 2 40 41 42 45", "1 0 0 1 0",
+		"hatoslotto 1 2 3 4 5 46", "ERROR field 6 '46': picked number is out of range",
+		"hatoslotto 1 2 3 4 5", "ERROR invalid quantity of picked numbers",
+		"skandinav 1 2 3 4 5 6 7", "ERROR unknown game 'skandinav'",
+		"", "ERROR missing game",
+	})
+	assert.NoError(t, router.Close())
+}
+
+func TestReloadGamesThroughAdmin(t *testing.T) {
+	loader := func(game lottery.Game) Loader {
+		return func(ctx context.Context, paths []string) (lottery.Registry, int, error) {
+			registry := lottery.NewGameRegistry(game, nil)
+			registry.RegisterPlayer(1, []lottery.Number{1, 2, 3, 4, 5, 6, 7}[:game.NumPicks])
+			registry.BeReadyForProcessing()
+			return registry, 1, nil
+		}
+	}
+
+	router := NewRouter()
+	for _, game := range []lottery.Game{lottery.Otoslotto, lottery.Skandinav} {
+		server, err := NewReloadable(context.Background(), loader(game), []string{game.Name + ".txt"})
+		assert.NoError(t, err)
+		server.Game = game
+		router.Handle(game.Name, server)
+	}
+
+	exchange(t, listen(t, router.ServeAdmin), []string{
+		"STATUS", "OK otoslotto=otoslotto.txt skandinav=skandinav.txt",
+		"RELOAD skandinav week-2.txt retail.txt", "OK week-2.txt retail.txt",
+		"RELOAD hatoslotto", "ERROR unknown game 'hatoslotto'",
+		"RELOAD", "ERROR missing game",
+		"STATUS", "OK otoslotto=otoslotto.txt skandinav=week-2.txt,retail.txt",
+		"RESTART", "ERROR unknown command 'RESTART'",
+	})
+	exchange(t, listen(t, router.Serve), []string{
+		"skandinav 1 2 3 4 5 6 7", "0 0 0 0 0 1",
+	})
+	assert.NoError(t, router.Close())
+}
@@ -0,0 +1,363 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+	"github.com/felipead/hungarian-lottery/pkg/parsing"
+)
+
+var (
+	ErrReloadUnsupported = errors.New("reload is not supported without a loader")
+	ErrReloadInProgress  = errors.New("another reload is in progress")
+	ErrClosed            = errors.New("server is closed")
+	ErrSingleDraw        = errors.New("game has a single draw")
+)
+
+// Loader loads the ticket files with the given paths into a new registry, giving up if the context is done. It returns
+// the number of tickets loaded along with the registry.
+type Loader func(ctx context.Context, paths []string) (lottery.Registry, int, error)
+
+// Server serves lottery draws over a line-based protocol, typically on TCP. Clients send the lottery picks, one draw
+// per line, in the same format accepted by [parsing.ParseLine], and the server answers each line with either the
+// report of the draw, or an error message starting with "ERROR", in which case the connection remains open. A report
+// whose matches do not add up to the tickets of the registry is answered with an error as well.
+//
+// A server with a [Loader] operates as a long-running service: every week, a new ticket file can be loaded into a
+// fresh registry in the background, which is atomically swapped in once ready, without a restart.
+type Server struct {
+	// Deadline aborts draws taking longer than this, counting from their arrival. Zero means no deadline.
+	Deadline time.Duration
+
+	// Game determines the rules of the draws, [lottery.Otoslotto] if unset. It must match the game of the registry.
+	Game lottery.Game
+
+	current atomic.Pointer[slot]
+
+	// ctx bounds loading, so that it is canceled on shutdown.
+	ctx       context.Context
+	loader    Loader
+	reloading atomic.Bool
+}
+
+// slot holds a registry, along with the paths it was loaded from, and the number of tickets loaded.
+type slot struct {
+	registry lottery.Registry
+	paths    []string
+	tickets  int
+
+	// mutex serializes draws, since a registry processes a single draw at a time.
+	mutex sync.Mutex
+
+	// retired is set once the slot was swapped out, and its registry closed. Guarded by the mutex.
+	retired bool
+}
+
+// New creates a new [Server] for the given registry, which must be ready for processing, having the given number of
+// tickets. It cannot be reloaded.
+func New(registry lottery.Registry, tickets int) *Server {
+	s := &Server{ctx: context.Background()}
+	s.current.Store(&slot{registry: registry, tickets: tickets})
+	return s
+}
+
+// NewReloadable creates a new [Server], loading its registry from the given paths with the loader, which is used
+// again on every reload. The context bounds the initial load and all reloads, eg: being canceled on shutdown.
+func NewReloadable(ctx context.Context, loader Loader, paths []string) (*Server, error) {
+	s := &Server{ctx: ctx, loader: loader}
+	if err := s.Reload(paths); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Serve accepts connections from the listener, serving draws on each one of them concurrently, until the listener is
+// closed. For games having dual draws, such as [lottery.Skandinav], a line holding two draws separated by a vertical
+// bar, machine draw first, is processed as a dual draw. See [Server.ProcessDual]. Other games answer such a line
+// with an error.
+func (s *Server) Serve(listener net.Listener) error {
+	return serveLines(listener, s.handleDraw)
+}
+
+// ServeAdmin accepts connections from the listener, serving admin commands, until the listener is closed. Each
+// command is a line, answered with a line starting with either "OK" or "ERROR". The commands are:
+//
+//	RELOAD [path]...  loads the ticket files from the given paths, or the current ones, and swaps them in once ready
+//	STATUS            answers with the paths of the current ticket files
+func (s *Server) ServeAdmin(listener net.Listener) error {
+	return serveLines(listener, s.handleAdmin)
+}
+
+// Process processes a single draw, returning its report. Draws are processed by the current registry; a reload does
+// not affect draws already in progress, which complete on the previous registry. A draw exceeding the deadline is
+// aborted with [lottery.ErrDeadlineExceeded], leaving the registry ready for the next draw. A report not adding up to
+// the tickets of the registry fails with an error wrapping [lottery.ErrInconsistentReport].
+func (s *Server) Process(picks []lottery.Number) (lottery.Report, error) {
+	var report lottery.Report
+	tickets, err := s.withRegistry(func(ctx context.Context, registry lottery.Registry) (err error) {
+		report, err = lottery.ProcessLotteryPicksContext(ctx, registry, picks)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = lottery.CheckConsistency(report, tickets); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// ProcessDual processes both the machine and the manual draw of a game such as [lottery.Skandinav], as
+// [Server.Process] does, returning the report of each draw. No other draw is processed in between, and the deadline
+// applies to both draws together.
+func (s *Server) ProcessDual(machine []lottery.Number, manual []lottery.Number) (lottery.DualReport, error) {
+	var report lottery.DualReport
+	tickets, err := s.withRegistry(func(ctx context.Context, registry lottery.Registry) (err error) {
+		report, err = lottery.ProcessDualDrawContext(ctx, registry, machine, manual)
+		return err
+	})
+	if err != nil {
+		return lottery.DualReport{}, err
+	}
+	if err = lottery.CheckConsistency(report.Machine, tickets); err != nil {
+		return lottery.DualReport{}, fmt.Errorf("machine draw: %w", err)
+	}
+	if err = lottery.CheckConsistency(report.Manual, tickets); err != nil {
+		return lottery.DualReport{}, fmt.Errorf("manual draw: %w", err)
+	}
+	return report, nil
+}
+
+// withRegistry invokes the given processing with the current registry, holding it exclusively, bounded by the
+// deadline, returning the number of tickets of the registry. The registry is reset afterwards, unless the processing
+// failed, in which case it was already reset.
+func (s *Server) withRegistry(process func(ctx context.Context, registry lottery.Registry) error) (int, error) {
+	ctx := context.Background()
+	if s.Deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.Deadline)
+		defer cancel()
+	}
+
+	for {
+		current := s.current.Load()
+		current.mutex.Lock()
+
+		//
+		// The slot may have been swapped out and retired while waiting for the lock, in which case the draw is
+		// processed by the current one instead.
+		//
+		if current.retired {
+			current.mutex.Unlock()
+			if s.current.Load() == current {
+				return 0, ErrClosed
+			}
+			continue
+		}
+
+		err := process(ctx, current.registry)
+		if err == nil {
+			current.registry.ResetLastProcessing()
+		}
+		current.mutex.Unlock()
+		return current.tickets, err
+	}
+}
+
+// Reload loads the ticket files from the given paths, or the current ones if none is given, into a fresh registry.
+// Meanwhile, draws keep being processed by the current registry. Once the fresh registry is ready for processing, it
+// is atomically swapped in. Only one reload may happen at a time. If loading fails, the current registry is kept. If
+// the server was closed meanwhile, the fresh registry is closed as well, failing with [ErrClosed].
+func (s *Server) Reload(paths []string) error {
+	if s.loader == nil {
+		return ErrReloadUnsupported
+	}
+	if !s.reloading.CompareAndSwap(false, true) {
+		return ErrReloadInProgress
+	}
+	defer s.reloading.Store(false)
+
+	if len(paths) == 0 {
+		paths = s.Paths()
+	}
+
+	registry, tickets, err := s.loader(s.ctx, paths)
+	if err != nil {
+		return err
+	}
+	registry.BeReadyForProcessing()
+	fresh := &slot{registry: registry, paths: paths, tickets: tickets}
+
+	previous := s.current.Load()
+	if previous == nil {
+		s.current.Store(fresh)
+		return nil
+	}
+
+	//
+	// The previous slot is swapped out while holding its lock, so that a concurrent Close either retires it first,
+	// in which case the server is closed and the fresh registry is not swapped in, or retires the fresh one next.
+	//
+	previous.mutex.Lock()
+	defer previous.mutex.Unlock()
+
+	if previous.retired {
+		if err := fresh.retireLocked(); err != nil {
+			log.Warnf("unable to close the fresh registry: %v", err)
+		}
+		return ErrClosed
+	}
+
+	s.current.Store(fresh)
+	log.Infof("swapped in registry loaded from %v", strings.Join(paths, ", "))
+	if err := previous.retireLocked(); err != nil {
+		log.Warnf("unable to close the previous registry: %v", err)
+	}
+
+	return nil
+}
+
+// Close closes the current registry, if it holds resources such as files, once the draws in progress are done.
+// Draws received afterwards fail with [ErrClosed].
+func (s *Server) Close() error {
+	for {
+		//
+		// A reload may swap in a fresh registry while the current one is being retired, in which case the fresh one
+		// is retired as well.
+		//
+		current := s.current.Load()
+		err := current.retire()
+		if s.current.Load() == current {
+			return err
+		}
+	}
+}
+
+// retire closes the registry of a slot which is no longer used, if it holds resources such as files, once the draws
+// in progress are done.
+func (s *slot) retire() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.retireLocked()
+}
+
+// retireLocked retires the slot as retire does, while its mutex is already held, or the slot is not shared yet.
+func (s *slot) retireLocked() error {
+	if s.retired {
+		return nil
+	}
+	s.retired = true
+	if closer, ok := s.registry.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Paths returns the paths the current registry was loaded from, if known.
+func (s *Server) Paths() []string {
+	if current := s.current.Load(); current != nil {
+		return current.paths
+	}
+	return nil
+}
+
+func (s *Server) handleDraw(line string) string {
+	game := s.Game
+	if game == (lottery.Game{}) {
+		game = lottery.Otoslotto
+	}
+
+	//
+	// Two draws separated by a vertical bar are a dual draw, machine draw first, answered with the report of each.
+	//
+	machineLine, manualLine, dual := strings.Cut(line, "|")
+	if dual && !game.Dual {
+		return fmt.Sprintf("ERROR %v: %v", ErrSingleDraw, game.Name)
+	}
+
+	machine := make([]lottery.Number, game.NumPicks)
+	if err := parsing.ParseGameLine(game, machineLine, machine); err != nil && dual {
+		return fmt.Sprintf("ERROR machine draw: %v", err)
+	} else if err != nil {
+		return fmt.Sprintf("ERROR %v", err)
+	}
+
+	var report fmt.Stringer
+	var err error
+	if dual {
+		manual := make([]lottery.Number, game.NumPicks)
+		if err = parsing.ParseGameLine(game, manualLine, manual); err != nil {
+			return fmt.Sprintf("ERROR manual draw: %v", err)
+		}
+		report, err = s.ProcessDual(machine, manual)
+	} else {
+		report, err = s.Process(machine)
+	}
+
+	if err != nil {
+		return fmt.Sprintf("ERROR %v", err)
+	}
+	return report.String()
+}
+
+func (s *Server) handleAdmin(line string) string {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return "ERROR missing command"
+	}
+
+	switch strings.ToUpper(fields[0]) {
+	case "RELOAD":
+		if err := s.Reload(fields[1:]); err != nil {
+			return fmt.Sprintf("ERROR %v", err)
+		}
+		return "OK " + strings.Join(s.Paths(), " ")
+	case "STATUS":
+		return "OK " + strings.Join(s.Paths(), " ")
+	default:
+		return fmt.Sprintf("ERROR unknown command '%v'", fields[0])
+	}
+}
+
+// serveLines accepts connections from the listener, answering each line received with a line, until the listener is
+// closed.
+func serveLines(listener net.Listener, handle func(line string) string) error {
+	for {
+		conn, err := listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		go serveConnection(conn, handle)
+	}
+}
+
+func serveConnection(conn net.Conn, handle func(line string) string) {
+	defer func() { _ = conn.Close() }()
+
+	scanner := bufio.NewScanner(conn)
+	writer := bufio.NewWriter(conn)
+
+	for scanner.Scan() {
+		_, _ = fmt.Fprintln(writer, handle(scanner.Text()))
+
+		if err := writer.Flush(); err != nil {
+			log.Warnf("unable to reply to %v: %v", conn.RemoteAddr(), err)
+			return
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/felipead/hungarian-lottery/pkg/lottery"
+)
+
+func TestServeDrawsPerLine(t *testing.T) {
+	registry := newRegistry(
+		[]lottery.Number{11, 22, 33, 44, 55},
+		[]lottery.Number{11, 22, 33, 44, 66},
+		[]lottery.Number{11, 22, 77, 88, 66},
+	)
+	registry.BeReadyForProcessing()
+	server := New(registry, 3)
+
+	exchange(t, listen(t, server.Serve), []string{
+		"11 22 33 44 55", "1 0 1 1",
+		"11 22 33 44 91", "ERROR field 5 '91': picked number is out of range",
+		"11 22 77 88 66", "1 1 0 1",
+	})
+}
+
+func TestReloadSwapsRegistryOnceReady(t *testing.T) {
+	loading := make(chan struct{})
+	release := make(chan struct{})
+	loader := func(_ context.Context, paths []string) (lottery.Registry, int, error) {
+		if paths[0] == "week-2.txt" {
+			close(loading)
+			<-release
+			return newRegistry([]lottery.Number{11, 22, 33, 44, 55}, []lottery.Number{11, 22, 33, 44, 55}), 2, nil
+		}
+		return newRegistry([]lottery.Number{11, 22, 33, 44, 55}), 1, nil
+	}
+
+	server, err := NewReloadable(context.Background(), loader, []string{"week-1.txt"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"week-1.txt"}, server.Paths())
+
+	reloaded := make(chan error)
+	go func() { reloaded <- server.Reload([]string{"week-2.txt"}) }()
+
+	//
+	// While the new week's file is loading, draws are processed by the current registry, and no other reload may
+	// start.
+	//
+	<-loading
+	assert.ErrorIs(t, server.Reload(nil), ErrReloadInProgress)
+	assertReport(t, "0 0 0 1", server, []lottery.Number{11, 22, 33, 44, 55})
+
+	close(release)
+	assert.NoError(t, <-reloaded)
+
+	assert.Equal(t, []string{"week-2.txt"}, server.Paths())
+	assertReport(t, "0 0 0 2", server, []lottery.Number{11, 22, 33, 44, 55})
+}
+
+func TestReloadKeepsRegistryIfLoadingFails(t *testing.T) {
+	failure := errors.New("no such file")
+	loader := func(_ context.Context, paths []string) (lottery.Registry, int, error) {
+		if paths[0] == "missing.txt" {
+			return nil, 0, failure
+		}
+		return newRegistry([]lottery.Number{11, 22, 33, 44, 55}), 1, nil
+	}
+
+	server, err := NewReloadable(context.Background(), loader, []string{"week-1.txt"})
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, server.Reload([]string{"missing.txt"}), failure)
+	assert.Equal(t, []string{"week-1.txt"}, server.Paths())
+	assertReport(t, "0 0 0 1", server, []lottery.Number{11, 22, 33, 44, 55})
+}
+
+func TestReloadClosesPreviousRegistry(t *testing.T) {
+	week1, err := lottery.NewDiskRegistry(t.TempDir(), 0)
+	assert.NoError(t, err)
+	week1.RegisterPlayer(1, []lottery.Number{11, 22, 33, 44, 55})
+
+	loader := func(_ context.Context, paths []string) (lottery.Registry, int, error) {
+		if paths[0] == "week-2.txt" {
+			return newRegistry([]lottery.Number{11, 22, 33, 44, 55}, []lottery.Number{11, 22, 33, 44, 55}), 2, nil
+		}
+		return week1, 1, nil
+	}
+
+	server, err := NewReloadable(context.Background(), loader, []string{"week-1.txt"})
+	assert.NoError(t, err)
+	assertReport(t, "0 0 0 1", server, []lottery.Number{11, 22, 33, 44, 55})
+
+	assert.NoError(t, server.Reload([]string{"week-2.txt"}))
+
+	_, err = lottery.ProcessLotteryPicksContext(context.Background(), week1, []lottery.Number{11, 22, 33, 44, 55})
+	assert.Error(t, err)
+	assertReport(t, "0 0 0 2", server, []lottery.Number{11, 22, 33, 44, 55})
+
+	assert.NoError(t, server.Close())
+	_, err = server.Process([]lottery.Number{11, 22, 33, 44, 55})
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestReloadClosesFreshRegistryIfServerWasClosed(t *testing.T) {
+	week2, err := lottery.NewDiskRegistry(t.TempDir(), 0)
+	assert.NoError(t, err)
+	week2.RegisterPlayer(1, []lottery.Number{11, 22, 33, 44, 55})
+
+	loading := make(chan struct{})
+	release := make(chan struct{})
+	loader := func(_ context.Context, paths []string) (lottery.Registry, int, error) {
+		if paths[0] == "week-2.txt" {
+			close(loading)
+			<-release
+			return week2, 1, nil
+		}
+		return newRegistry([]lottery.Number{11, 22, 33, 44, 55}), 1, nil
+	}
+
+	server, err := NewReloadable(context.Background(), loader, []string{"week-1.txt"})
+	assert.NoError(t, err)
+
+	reloaded := make(chan error)
+	go func() { reloaded <- server.Reload([]string{"week-2.txt"}) }()
+
+	<-loading
+	assert.NoError(t, server.Close())
+	close(release)
+	assert.ErrorIs(t, <-reloaded, ErrClosed)
+
+	_, err = lottery.ProcessLotteryPicksContext(context.Background(), week2, []lottery.Number{11, 22, 33, 44, 55})
+	assert.Error(t, err)
+	_, err = server.Process([]lottery.Number{11, 22, 33, 44, 55})
+	assert.ErrorIs(t, err, ErrClosed)
+	assert.Equal(t, []string{"week-1.txt"}, server.Paths())
+}
+
+func TestReloadUnsupportedWithoutLoader(t *testing.T) {
+	registry := newRegistry([]lottery.Number{11, 22, 33, 44, 55})
+	registry.BeReadyForProcessing()
+
+	assert.ErrorIs(t, New(registry, 1).Reload(nil), ErrReloadUnsupported)
+}
+
+func TestServeAdminCommands(t *testing.T) {
+	loads := 0
+	loader := func(_ context.Context, paths []string) (lottery.Registry, int, error) {
+		loads++
+		tickets := make([][]lottery.Number, loads)
+		for i := range tickets {
+			tickets[i] = []lottery.Number{11, 22, 33, 44, 55}
+		}
+		return newRegistry(tickets...), len(tickets), nil
+	}
+
+	server, err := NewReloadable(context.Background(), loader, []string{"week-1.txt"})
+	assert.NoError(t, err)
+
+	exchange(t, listen(t, server.ServeAdmin), []string{
+		"STATUS", "OK week-1.txt",
+		"RELOAD", "OK week-1.txt",
+		"reload week-2.txt retail.csv", "OK week-2.txt retail.csv",
+		"", "ERROR missing command",
+		"RESTART", "ERROR unknown command 'RESTART'",
+	})
+	assertReport(t, "0 0 0 3", server, []lottery.Number{11, 22, 33, 44, 55})
+}
+
+func TestAbortDrawExceedingDeadline(t *testing.T) {
+	registry := newRegistry([]lottery.Number{11, 22, 33, 44, 55})
+	registry.BeReadyForProcessing()
+	server := New(registry, 1)
+
+	server.Deadline = time.Nanosecond
+	_, err := server.Process([]lottery.Number{11, 22, 33, 44, 55})
+	assert.ErrorIs(t, err, lottery.ErrDeadlineExceeded)
+
+	server.Deadline = time.Minute
+	assertReport(t, "0 0 0 1", server, []lottery.Number{11, 22, 33, 44, 55})
+}
+
+func assertReport(t *testing.T, expected string, server *Server, picks []lottery.Number) {
+	report, err := server.Process(picks)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, report.String())
+}
+
+func newRegistry(tickets ...[]lottery.Number) lottery.Registry {
+	registry := lottery.NewRegistry()
+	for i, picks := range tickets {
+		registry.RegisterPlayer(lottery.PlayerID(i+1), picks)
+	}
+	return registry
+}
+
+// listen serves on a local port, until the test finishes, returning its address.
+func listen(t *testing.T, serve func(net.Listener) error) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go func() { _ = serve(listener) }()
+	t.Cleanup(func() { _ = listener.Close() })
+	return listener.Addr().String()
+}
+
+// exchange sends each request, asserting its reply, given as pairs of request and reply.
+func exchange(t *testing.T, address string, requestsAndReplies []string) {
+	conn, err := net.Dial("tcp", address)
+	assert.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	replies := bufio.NewScanner(conn)
+
+	for i := 0; i < len(requestsAndReplies); i += 2 {
+		_, err = fmt.Fprintln(conn, requestsAndReplies[i])
+		assert.NoError(t, err)
+		assert.True(t, replies.Scan())
+		assert.Equal(t, requestsAndReplies[i+1], replies.Text())
+	}
+}
+
+func TestServeDualDraws(t *testing.T) {
+	registry := lottery.NewGameRegistry(lottery.Skandinav, nil)
+	registry.RegisterPlayer(1, []lottery.Number{1, 2, 3, 4, 5, 6, 7})
+	registry.RegisterPlayer(2, []lottery.Number{1, 2, 3, 29, 30, 31, 32})
+	registry.BeReadyForProcessing()
+	server := New(registry, 2)
+	server.Game = lottery.Skandinav
+
+	exchange(t, listen(t, server.Serve), []string{
+		"1 2 3 4 5 6 7 | 29 30 31 32 33 34 35", "0 1 0 0 0 1 | 0 0 1 0 0 0",
+		"1 2 3 4 5 6 7", "0 1 0 0 0 1",
+		"1 2 3 4 5 6 | 29 30 31 32 33 34 35", "ERROR machine draw: invalid quantity of picked numbers",
+		"1 2 3 4 5 6 7 | 29 30 31 32 33 34 36", "ERROR manual draw: field 7 '36': picked number is out of range",
+	})
+}
+
+func TestServeDualDrawsOnlyForDualGames(t *testing.T) {
+	for _, game := range []lottery.Game{lottery.Otoslotto, lottery.Hatoslotto} {
+		registry := lottery.NewGameRegistry(game, nil)
+		registry.BeReadyForProcessing()
+		server := New(registry, 0)
+		server.Game = game
+
+		line := "1 2 3 4 5 6 | 7 8 9 10 11 12"
+		if game == lottery.Otoslotto {
+			line = "1 2 3 4 5 | 6 7 8 9 10"
+		}
+
+		exchange(t, listen(t, server.Serve), []string{line, "ERROR game has a single draw: " + game.Name})
+	}
+
+	//
+	// The default game is Ötöslottó.
+	//
+	registry := lottery.NewRegistry()
+	registry.BeReadyForProcessing()
+	exchange(t, listen(t, New(registry, 0).Serve), []string{
+		"1 2 3 4 5 | 6 7 8 9 10", "ERROR game has a single draw: otoslotto",
+		"1 2 3 4 5", "0 0 0 0",
+	})
+}
+
+func TestServeInconsistentReportAsError(t *testing.T) {
+	registry := newRegistry([]lottery.Number{11, 22, 33, 44, 55})
+	registry.BeReadyForProcessing()
+
+	exchange(t, listen(t, New(registry, 2).Serve), []string{
+		"11 22 33 44 55", "ERROR inconsistent report: matches add up to 1 tickets, but there are 2",
+	})
+}